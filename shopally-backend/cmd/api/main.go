@@ -57,26 +57,206 @@ func main() {
 		time.Duration(cfg.RateLimit.Window)*time.Second,
 	)
 
+	// Record/replay fixtures for the upstream APIs, selected by config
+	fixtures, err := gateway.NewFixtureTransportFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("failed to set up fixtures: %v", err)
+	}
+
 	// FX client (provider defaults to exchangerate.host if not configured)
-	fxInner := gateway.NewFXHTTPGateway("", "", nil)
+	fxInner := gateway.NewFXHTTPGateway("", "", fixtures.Client(15*time.Second))
 	var fxClient domain.IFXClient = fxInner
+	// Search result cache (nil disables caching)
+	var searchCache domain.CacheGateway
 	// Wrap with Redis cache if available
 	if rdb != nil {
-		redisCache := gateway.NewRedisCache(rdb.Client, "sa:")
+		redisCache := gateway.NewRedisCache(rdb.Client, cfg.Redis.KeyPrefix)
 		fxClient = gateway.NewCachedFXClient(fxInner, redisCache, 12*time.Hour)
+		searchCache = gateway.NewCacheGateway(redisCache)
 	}
 
 	// Choose LLM implementation
-	lg := gateway.NewGeminiLLMGateway(cfg.Gemini.APIKey, fxClient)
+	geminiKey := cfg.Gemini.APIKey
+	if geminiKey == "" && fixtures != nil && fixtures.Mode() == gateway.FixtureReplay {
+		geminiKey = "replay" // fixtures never hold the real key
+	}
+	lg := gateway.NewGeminiLLMGateway(geminiKey, fxClient, fixtures.Client(12*time.Second))
 
 	// Alibaba gateway: use HTTP gateway (real) and pass configuration
 	// If you want to force the mock gateway for local development, replace
 	// the following line with: ag := gateway.NewMockAlibabaGateway()
-	ag := gateway.NewAlibabaHTTPGateway(cfg)
+	// (and drop the circuit breaker health check below).
+	ag := gateway.NewAlibabaHTTPGateway(cfg, fixtures.Client(10*time.Second))
+
+	// Affiliate links and click tracking shared by every product surface
+	linkSecret := cfg.Affiliate.LinkSecret
+	if linkSecret == "" {
+		linkSecret = cfg.Aliexpress.AppSecret
+	}
+	if linkSecret == "" {
+		// Unsigned tokens would let anyone mint redirect links.
+		log.Fatal("affiliate.link_secret or aliexpress.app_secret must be set to sign tracked links")
+	}
+	affiliate := usecase.NewAffiliateLinker(ag, searchCache, linkSecret)
+	affiliate.BaseURL = cfg.Affiliate.RedirectBaseURL
+	if cfg.Affiliate.LinkCacheTTLSeconds > 0 {
+		affiliate.CacheTTL = time.Duration(cfg.Affiliate.LinkCacheTTLSeconds) * time.Second
+	}
+
+	// Landed-cost engine shared by search, product detail and compare
+	landedSettings := usecase.DefaultLandedCostSettings()
+	if cfg.LandedCost.DutyRate != nil {
+		landedSettings.Duty = *cfg.LandedCost.DutyRate
+	}
+	if cfg.LandedCost.ExciseRate != nil {
+		landedSettings.Excise = *cfg.LandedCost.ExciseRate
+	}
+	if cfg.LandedCost.VATRate != nil {
+		landedSettings.VAT = *cfg.LandedCost.VATRate
+	}
+	if cfg.LandedCost.SurtaxRate != nil {
+		landedSettings.Surtax = *cfg.LandedCost.SurtaxRate
+	}
+	landedSettings.ShippingUSD = cfg.LandedCost.ShippingUSD
+	for _, r := range cfg.LandedCost.Rules {
+		landedSettings.Rules = append(landedSettings.Rules, usecase.LandedCostRule{CategoryIDs: r.CategoryIDs, Duty: r.Duty, Excise: r.Excise})
+	}
+	landedCost := usecase.NewLandedCostCalculator(landedSettings)
 
 	// Construct usecase and handler for search
-	uc := usecase.NewSearchProductsUseCase(ag, lg, nil)
-	searchHandler := handler.NewSearchHandler(uc)
+	uc := usecase.NewSearchProductsUseCase(ag, lg, searchCache, fxClient)
+	uc.LandedCost = landedCost
+	uc.Affiliate = affiliate
+	if cfg.Search.CacheTTLSeconds > 0 {
+		uc.CacheTTL = time.Duration(cfg.Search.CacheTTLSeconds) * time.Second
+	}
+	if cfg.Search.StaleTTLSeconds > 0 {
+		uc.StaleTTL = time.Duration(cfg.Search.StaleTTLSeconds) * time.Second
+	}
+	if cfg.Search.FallbackTTLSeconds > 0 {
+		uc.FallbackTTL = time.Duration(cfg.Search.FallbackTTLSeconds) * time.Second
+	}
+	if cfg.Search.SummaryConcurrency > 0 {
+		uc.SummaryConcurrency = cfg.Search.SummaryConcurrency
+	}
+	if cfg.Search.SummaryTimeoutSeconds > 0 {
+		uc.SummaryTimeout = time.Duration(cfg.Search.SummaryTimeoutSeconds) * time.Second
+	}
+	if cfg.Search.SummaryBudgetSeconds > 0 {
+		uc.SummaryBudget = time.Duration(cfg.Search.SummaryBudgetSeconds) * time.Second
+	}
+	if cfg.Search.CandidateTarget > 0 {
+		uc.CandidateTarget = cfg.Search.CandidateTarget
+	}
+	if cfg.Search.FetchConcurrency > 0 {
+		uc.FetchConcurrency = cfg.Search.FetchConcurrency
+	}
+	if cfg.Search.FetchIntervalMillis > 0 {
+		uc.FetchInterval = time.Duration(cfg.Search.FetchIntervalMillis) * time.Millisecond
+	}
+	if cfg.Search.BackfillPages > 0 {
+		uc.BackfillPages = cfg.Search.BackfillPages
+	}
+	if cfg.Search.SessionTTLSeconds > 0 {
+		uc.SessionTTL = time.Duration(cfg.Search.SessionTTLSeconds) * time.Second
+	}
+	if len(cfg.Search.Ranking) > 0 {
+		overrides := make(map[string]usecase.RankWeights, len(cfg.Search.Ranking))
+		for name, w := range cfg.Search.Ranking {
+			overrides[name] = usecase.RankWeights{
+				Relevance:  w.Relevance,
+				Price:      w.Price,
+				Delivery:   w.Delivery,
+				Rating:     w.Rating,
+				Seller:     w.Seller,
+				Sales:      w.Sales,
+				Discount:   w.Discount,
+				Popularity: w.Popularity,
+			}
+		}
+		uc.Rankers = usecase.NewRankers(overrides)
+	}
+
+	// Content policy: configured term lists plus terms managed in Mongo
+	termsColl := cfg.Policy.TermsCollection
+	if termsColl == "" {
+		termsColl = "policy_terms"
+	}
+	blockedColl := cfg.Policy.BlockedCollection
+	if blockedColl == "" {
+		blockedColl = "policy_blocked"
+	}
+	policyRepo := repo.NewMongoPolicyRepository(db.Collection(termsColl), db.Collection(blockedColl))
+	policy := usecase.NewContentPolicy(policyRepo, cfg.Policy.BlockedTerms)
+	refreshPolicy := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := policy.Refresh(ctx); err != nil {
+			log.Printf("failed to load policy terms from MongoDB: %v (keeping current terms)", err)
+		}
+	}
+	refreshPolicy()
+	policyRefresh := 5 * time.Minute
+	if cfg.Policy.RefreshIntervalSeconds > 0 {
+		policyRefresh = time.Duration(cfg.Policy.RefreshIntervalSeconds) * time.Second
+	}
+	go func() {
+		for range time.Tick(policyRefresh) {
+			refreshPolicy()
+		}
+	}()
+	uc.Policy = policy
+
+	// Category tree synced into Mongo by the worker, reloaded periodically
+	categoryColl := cfg.Categories.Collection
+	if categoryColl == "" {
+		categoryColl = "categories"
+	}
+	categories := usecase.NewCategoryCatalog(repo.NewMongoCategoryRepository(db.Collection(categoryColl)), cfg.Categories.Aliases)
+	refreshCategories := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := categories.Refresh(ctx); err != nil {
+			log.Printf("failed to load categories from MongoDB: %v", err)
+		}
+	}
+	refreshCategories()
+	categoryRefresh := time.Hour
+	if cfg.Categories.RefreshIntervalSeconds > 0 {
+		categoryRefresh = time.Duration(cfg.Categories.RefreshIntervalSeconds) * time.Second
+	}
+	go func() {
+		for range time.Tick(categoryRefresh) {
+			refreshCategories()
+		}
+	}()
+	uc.Categories = categories
+	categoryHandler := handler.NewCategoryHandler(categories)
+	searchHandler := handler.NewSearchHandler(uc, policy)
+
+	// Product snapshots and price history
+	productColl := cfg.Products.Collection
+	if productColl == "" {
+		productColl = "products"
+	}
+	pricePointColl := cfg.Products.PricePointCollection
+	if pricePointColl == "" {
+		pricePointColl = "price_points"
+	}
+	productRepo := repo.NewMongoProductRepository(db.Collection(productColl), db.Collection(pricePointColl))
+	indexCtx, indexCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := productRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Printf("failed to create price history indexes: %v", err)
+	}
+	indexCancel()
+	uc.Prices = usecase.NewPriceTracker(productRepo)
+	if cfg.Products.PricePointIntervalSeconds > 0 {
+		uc.Prices.MinInterval = time.Duration(cfg.Products.PricePointIntervalSeconds) * time.Second
+	}
+	if cfg.Products.MaxPendingWrites > 0 {
+		uc.Prices.MaxPendingWrites = cfg.Products.MaxPendingWrites
+	}
+	priceHistoryHandler := handler.NewPriceHistoryHandler(usecase.NewGetPriceHistoryUseCase(productRepo))
 
 	// Alerts: set up Mongo repository and handler
 	collName := cfg.Mongo.AlertCollection
@@ -88,10 +268,65 @@ func main() {
 	alertMgr := usecase.NewAlertManager(alertRepo)
 
 	alertHandler := handler.NewAlertHandler(alertMgr)
-	compareHandler := handler.NewCompareHandler(usecase.NewCompareProductsUseCase(lg))
+	compareUC := usecase.NewCompareProductsUseCase(lg, fxClient)
+	compareUC.LandedCost = landedCost
+	compareUC.Affiliate = affiliate
+	compareHandler := handler.NewCompareHandler(compareUC)
+	landedCostHandler := handler.NewLandedCostHandler(usecase.NewEstimateLandedCostUseCase(landedCost, fxClient))
+
+	// Product detail shares the search cache and summary timeout
+	productUC := usecase.NewGetProductDetailUseCase(ag, lg, searchCache, fxClient)
+	if cfg.Search.ProductCacheTTLSeconds > 0 {
+		productUC.CacheTTL = time.Duration(cfg.Search.ProductCacheTTLSeconds) * time.Second
+	}
+	productUC.SummaryTimeout = uc.SummaryTimeout
+	productUC.LandedCost = landedCost
+	productUC.Affiliate = affiliate
+
+	// View tracking lives in Redis; without it the view endpoints are not served
+	var views *usecase.ProductViewsUseCase
+	var viewHandler *handler.ViewHandler
+	if rdb != nil {
+		viewTTL := time.Duration(cfg.Redis.ViewTrackingTTL) * time.Second
+		views = usecase.NewProductViewsUseCase(repo.NewRedisViewRepository(rdb.Client, cfg.Redis.KeyPrefix, viewTTL))
+		views.Products = productUC
+		if cfg.Views.PopularWindowSeconds > 0 {
+			views.PopularWindow = time.Duration(cfg.Views.PopularWindowSeconds) * time.Second
+		}
+		uc.Views = views
+		viewHandler = handler.NewViewHandler(views)
+	}
+	productHandler := handler.NewProductHandler(productUC, views)
+
+	// Deals pools are prefetched by the worker into the shared cache
+	dealsUC := usecase.NewGetDealsUseCase(ag, searchCache, fxClient)
+	if cfg.Deals.CacheTTLSeconds > 0 {
+		dealsUC.CacheTTL = time.Duration(cfg.Deals.CacheTTLSeconds) * time.Second
+	}
+	if cfg.Deals.PoolPages > 0 {
+		dealsUC.PoolPages = cfg.Deals.PoolPages
+	}
+	dealsUC.Ranker = uc.Rankers[usecase.RankBestDeal]
+	dealsUC.LandedCost = landedCost
+	dealsUC.Policy = policy
+	dealsUC.Affiliate = affiliate
+	dealsHandler := handler.NewDealsHandler(dealsUC)
+
+	clickColl := cfg.Affiliate.ClickCollection
+	if clickColl == "" {
+		clickColl = "clicks"
+	}
+	clickHandler := handler.NewClickHandler(usecase.NewTrackClickUseCase(repo.NewMongoClickRepository(db.Collection(clickColl)), affiliate))
+
+	healthHandler := handler.NewHealthHandler(map[string]handler.HealthCheck{
+		"aliexpress": func() (interface{}, bool) {
+			status := ag.CircuitStatus()
+			return status, status.State != gateway.CircuitClosed
+		},
+	})
 
 	// Initialize router
-	router := router.SetupRouter(cfg, limiter, searchHandler, compareHandler, alertHandler)
+	router := router.SetupRouter(cfg, limiter, searchHandler, compareHandler, alertHandler, productHandler, landedCostHandler, categoryHandler, dealsHandler, clickHandler, viewHandler, priceHistoryHandler, healthHandler)
 
 	// Start the server
 	log.Println("Starting server on port", cfg.Server.Port)
@@ -10,13 +10,16 @@ import (
 	"github.com/shopally-ai/pkg/domain"
 )
 
-func SetupRouter(cfg *config.Config, limiter *middleware.RateLimiter, searchHandler *handler.SearchHandler, compareHandler *handler.CompareHandler, alertHandler *handler.AlertHandler) *gin.Engine {
+func SetupRouter(cfg *config.Config, limiter *middleware.RateLimiter, searchHandler *handler.SearchHandler, compareHandler *handler.CompareHandler, alertHandler *handler.AlertHandler, productHandler *handler.ProductHandler, landedCostHandler *handler.LandedCostHandler, categoryHandler *handler.CategoryHandler, dealsHandler *handler.DealsHandler, clickHandler *handler.ClickHandler, viewHandler *handler.ViewHandler, priceHistoryHandler *handler.PriceHistoryHandler, healthHandler *handler.HealthHandler) *gin.Engine {
 	router := gin.Default()
 
+	// Tracked product links are opened by browsers, outside the versioned API
+	router.GET("/r/:token", clickHandler.Redirect)
+
 	version1 := router.Group("/api/v1")
 
 	// Health checker
-	version1.GET("/health", handler.Health)
+	version1.GET("/health", healthHandler.Health)
 
 	// private
 	limitedRouter := version1.Group("")
@@ -25,10 +28,18 @@ func SetupRouter(cfg *config.Config, limiter *middleware.RateLimiter, searchHand
 		limitedRouter.GET("/limited", func(c *gin.Context) {
 			c.JSON(http.StatusOK, domain.Response{Data: map[string]interface{}{"message": "limited message"}})
 		})
-		limitedRouter.POST("/compare", func(c *gin.Context) {
-			c.JSON(http.StatusOK, domain.Response{Data: map[string]interface{}{"message": "limited message"}})
-		})
+		limitedRouter.POST("/compare", compareHandler.CompareProducts)
 		limitedRouter.GET("/search", searchHandler.Search)
+		limitedRouter.GET("/search/stream", searchHandler.SearchStream)
+		limitedRouter.GET("/products/:id", productHandler.GetProduct)
+		limitedRouter.GET("/products/:id/price-history", priceHistoryHandler.GetPriceHistory)
+		if viewHandler != nil {
+			limitedRouter.POST("/products/:id/view", viewHandler.RecordView)
+			limitedRouter.GET("/me/recently-viewed", viewHandler.RecentlyViewed)
+		}
+		limitedRouter.POST("/landed-cost", landedCostHandler.Estimate)
+		limitedRouter.GET("/categories", categoryHandler.ListCategories)
+		limitedRouter.GET("/deals", dealsHandler.GetDeals)
 
 		// Alerts endpoints
 		limitedRouter.POST("/alerts", alertHandler.CreateAlertHandler)
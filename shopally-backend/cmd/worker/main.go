@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/internal/adapter/repository"
 	"github.com/shopally-ai/internal/config"
 	"github.com/shopally-ai/internal/platform"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
 )
 
 func main() {
@@ -23,7 +26,12 @@ func main() {
 	}
 	cache := gateway.NewRedisCache(rc.Client, cfg.Redis.KeyPrefix)
 
-	fxHTTP := gateway.NewFXHTTPGateway(cfg.FX.APIURL, cfg.FX.APIKEY, nil)
+	fixtures, err := gateway.NewFixtureTransportFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("fixtures: %v", err)
+	}
+
+	fxHTTP := gateway.NewFXHTTPGateway(cfg.FX.APIURL, cfg.FX.APIKEY, fixtures.Client(15*time.Second))
 	ttl := time.Duration(cfg.FX.CacheTTLSeconds) * time.Second
 	fx := gateway.NewCachedFXClient(fxHTTP, cache, ttl)
 
@@ -31,13 +39,86 @@ func main() {
 	warm := func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		if rate, err := fx.GetRate(ctx, "USD", "ETB"); err != nil {
+		if q, err := fx.GetQuote(ctx, "USD", "ETB"); err != nil {
 			log.Printf("worker warm fx error: %v", err)
 		} else {
-			log.Printf("worker warm fx USD->ETB: %.6f", rate)
+			log.Printf("worker warm fx USD->ETB: %.6f (fetched %s)", q.Rate, q.FetchedAt.Format(time.RFC3339))
+		}
+	}
+
+	// Sync the AliExpress category tree into Mongo
+	mongoClient, err := platform.Connect(cfg.Mongo.URI)
+	if err != nil {
+		log.Printf("worker mongo connect failed (category sync disabled): %v", err)
+	} else {
+		defer func() {
+			if err := platform.Disconnect(mongoClient); err != nil {
+				log.Printf("worker mongo disconnect: %v", err)
+			}
+		}()
+		categoryColl := cfg.Categories.Collection
+		if categoryColl == "" {
+			categoryColl = "categories"
+		}
+		syncCategories := usecase.NewSyncCategoriesUseCase(
+			gateway.NewAlibabaHTTPGateway(cfg, fixtures.Client(10*time.Second)),
+			repository.NewMongoCategoryRepository(mongoClient.Database(cfg.Mongo.Database).Collection(categoryColl)),
+		)
+		interval := 24 * time.Hour
+		if cfg.Categories.SyncIntervalSeconds > 0 {
+			interval = time.Duration(cfg.Categories.SyncIntervalSeconds) * time.Second
+		}
+		syncOnce := func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if n, err := syncCategories.Execute(ctx); err != nil {
+				log.Printf("worker category sync error: %v", err)
+			} else {
+				log.Printf("worker synced %d categories", n)
+			}
 		}
+		go func() {
+			syncOnce()
+			for range time.Tick(interval) {
+				syncOnce()
+			}
+		}()
 	}
 
+	// Prefetch the deals pools served by the API
+	deals := usecase.NewGetDealsUseCase(gateway.NewAlibabaHTTPGateway(cfg, fixtures.Client(10*time.Second)), gateway.NewCacheGateway(cache), fx)
+	if cfg.Deals.CacheTTLSeconds > 0 {
+		deals.CacheTTL = time.Duration(cfg.Deals.CacheTTLSeconds) * time.Second
+	}
+	if cfg.Deals.PoolPages > 0 {
+		deals.PoolPages = cfg.Deals.PoolPages
+	}
+	dealsInterval := 30 * time.Minute
+	if cfg.Deals.PrefetchIntervalSeconds > 0 {
+		dealsInterval = time.Duration(cfg.Deals.PrefetchIntervalSeconds) * time.Second
+	}
+	dealsFilters := []domain.DealsFilters{{}}
+	for _, id := range cfg.Deals.PrefetchCategoryIDs {
+		dealsFilters = append(dealsFilters, domain.DealsFilters{CategoryID: id})
+	}
+	prefetchDeals := func() {
+		for _, f := range dealsFilters {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if n, err := deals.Prefetch(ctx, f); err != nil {
+				log.Printf("worker deals prefetch error for %+v: %v", f, err)
+			} else {
+				log.Printf("worker prefetched %d deals for %+v", n, f)
+			}
+			cancel()
+		}
+	}
+	go func() {
+		prefetchDeals()
+		for range time.Tick(dealsInterval) {
+			prefetchDeals()
+		}
+	}()
+
 	warm()
 	ticker := time.NewTicker(30 * time.Minute)
 	defer ticker.Stop()
@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+)
+
+// maxAffiliateLinkBatch is the most source URLs link.generate accepts per call.
+const maxAffiliateLinkBatch = 50
+
+// errMissingTrackingID is returned when no affiliate tracking ID is
+// configured; links generated without one earn no commission.
+var errMissingTrackingID = errors.New("aliexpress tracking_id is not configured")
+
+// GenerateAffiliateLinks implements domain.AlibabaGateway using
+// aliexpress.affiliate.link.generate, in batches of maxAffiliateLinkBatch.
+// A failed batch fails the call; links from earlier batches are still returned.
+func (a *AlibabaHTTPGateway) GenerateAffiliateLinks(ctx context.Context, sourceURLs []string) (map[string]string, error) {
+	links := make(map[string]string, len(sourceURLs))
+	if len(sourceURLs) == 0 {
+		return links, nil
+	}
+	if a.cfg.Aliexpress.TrackingID == "" {
+		return links, errMissingTrackingID
+	}
+	log.Printf("[AlibabaGateway] GenerateAffiliateLinks called for %d URLs", len(sourceURLs))
+
+	for start := 0; start < len(sourceURLs); start += maxAffiliateLinkBatch {
+		batch := sourceURLs[start:min(start+maxAffiliateLinkBatch, len(sourceURLs))]
+		body, err := a.call(ctx, a.withTracking(map[string]string{
+			"method":              "aliexpress.affiliate.link.generate",
+			"promotion_link_type": "0",
+			"source_values":       strings.Join(batch, ","),
+		}))
+		if err != nil {
+			return links, err
+		}
+		generated, err := MapAliExpressLinkResponse(body)
+		if err != nil {
+			return links, err
+		}
+		for src, link := range generated {
+			links[src] = link
+		}
+	}
+	return links, nil
+}
+
+// withTracking adds the configured affiliate tracking ID to params.
+func (a *AlibabaHTTPGateway) withTracking(params map[string]string) map[string]string {
+	if id := a.cfg.Aliexpress.TrackingID; id != "" {
+		params["tracking_id"] = id
+	}
+	return params
+}
+
+// MapAliExpressLinkResponse maps a link.generate response to promotion links
+// keyed by the source URL they were generated for.
+func MapAliExpressLinkResponse(data []byte) (map[string]string, error) {
+	var resp struct {
+		AliexpressResp struct {
+			RespResult struct {
+				Result struct {
+					PromotionLinks struct {
+						PromotionLink []struct {
+							PromotionLink string `json:"promotion_link"`
+							SourceValue   string `json:"source_value"`
+						} `json:"promotion_link"`
+					} `json:"promotion_links"`
+				} `json:"result"`
+			} `json:"resp_result"`
+		} `json:"aliexpress_affiliate_link_generate_response"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, unreadableAliResponse("link response", err)
+	}
+
+	links := make(map[string]string)
+	for _, l := range resp.AliexpressResp.RespResult.Result.PromotionLinks.PromotionLink {
+		src, link := strings.TrimSpace(l.SourceValue), strings.TrimSpace(l.PromotionLink)
+		if src != "" && link != "" {
+			links[src] = link
+		}
+	}
+	return links, nil
+}
@@ -0,0 +1,34 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopally-ai/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapAliExpressLinkResponse(t *testing.T) {
+	raw := `{"aliexpress_affiliate_link_generate_response":{"resp_result":{"resp_code":200,"result":{
+		"promotion_links":{"promotion_link":[
+			{"promotion_link":"https://s.click.aliexpress.com/e/_abc","source_value":"https://www.aliexpress.com/item/1.html"},
+			{"promotion_link":"","source_value":"https://www.aliexpress.com/item/2.html"}
+		]},"total_result_count":2}}}}`
+
+	links, err := MapAliExpressLinkResponse([]byte(raw))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"https://www.aliexpress.com/item/1.html": "https://s.click.aliexpress.com/e/_abc",
+	}, links)
+
+	_, err = MapAliExpressLinkResponse([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestGenerateAffiliateLinks_RequiresTrackingID(t *testing.T) {
+	g := NewAlibabaHTTPGateway(&config.Config{}, nil)
+	links, err := g.GenerateAffiliateLinks(context.Background(), []string{"https://www.aliexpress.com/item/1.html"})
+	assert.ErrorIs(t, err, errMissingTrackingID)
+	assert.Empty(t, links)
+}
@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// FetchCategories implements domain.AlibabaGateway using
+// aliexpress.affiliate.category.get.
+func (a *AlibabaHTTPGateway) FetchCategories(ctx context.Context) ([]domain.Category, error) {
+	log.Println("[AlibabaGateway] FetchCategories called")
+
+	body, err := a.call(ctx, map[string]string{
+		"method": "aliexpress.affiliate.category.get",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return MapAliExpressCategoryResponse(body)
+}
+
+// MapAliExpressCategoryResponse maps a category.get response to categories.
+// The API returns two levels: categories without a parent are top level.
+func MapAliExpressCategoryResponse(data []byte) ([]domain.Category, error) {
+	var resp struct {
+		AliexpressResp struct {
+			RespResult struct {
+				Result struct {
+					Categories struct {
+						Category []struct {
+							CategoryID       int64  `json:"category_id"`
+							CategoryName     string `json:"category_name"`
+							ParentCategoryID int64  `json:"parent_category_id"`
+						} `json:"category"`
+					} `json:"categories"`
+				} `json:"result"`
+			} `json:"resp_result"`
+		} `json:"aliexpress_affiliate_category_get_response"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, unreadableAliResponse("category response", err)
+	}
+
+	out := make([]domain.Category, 0, len(resp.AliexpressResp.RespResult.Result.Categories.Category))
+	for _, c := range resp.AliexpressResp.RespResult.Result.Categories.Category {
+		if c.CategoryID == 0 {
+			continue
+		}
+		cat := domain.Category{
+			ID:    strconv.FormatInt(c.CategoryID, 10),
+			Name:  strings.TrimSpace(c.CategoryName),
+			Level: 1,
+		}
+		if c.ParentCategoryID != 0 {
+			cat.ParentID = strconv.FormatInt(c.ParentCategoryID, 10)
+			cat.Level = 2
+		}
+		out = append(out, cat)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Level < out[j].Level })
+	log.Println("Mapped", len(out), "categories from AliExpress response")
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockAliExpressCategoryResponse = `{
+    "aliexpress_affiliate_category_get_response": {
+        "resp_result": {
+            "resp_code": 200,
+            "result": {
+                "total_result_count": 3,
+                "categories": {
+                    "category": [
+                        {"category_id": 63705, "category_name": "Earphones & Headphones", "parent_category_id": 44},
+                        {"category_id": 44, "category_name": "Consumer Electronics "},
+                        {"category_name": "Broken"}
+                    ]
+                }
+            }
+        }
+    }
+}`
+
+func TestMapAliExpressCategoryResponse(t *testing.T) {
+	cats, err := MapAliExpressCategoryResponse([]byte(mockAliExpressCategoryResponse))
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.Category{
+		{ID: "44", Name: "Consumer Electronics", Level: 1},
+		{ID: "63705", ParentID: "44", Name: "Earphones & Headphones", Level: 2},
+	}, cats)
+
+	_, err = MapAliExpressCategoryResponse([]byte(`[`))
+	assert.Error(t, err)
+}
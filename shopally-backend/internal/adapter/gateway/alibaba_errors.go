@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// AliErrorKind classifies a failed AliExpress call.
+type AliErrorKind string
+
+const (
+	// AliErrorNetwork is a transport failure: DNS, connection or timeout.
+	AliErrorNetwork AliErrorKind = "network"
+	// AliErrorServer is a 5xx status or an "isp." (platform side) error code.
+	AliErrorServer AliErrorKind = "server"
+	// AliErrorRateLimited is a 429 status or a call-limit error code.
+	AliErrorRateLimited AliErrorKind = "rate_limited"
+	// AliErrorAuth is a 401/403 status or an error code rejecting the app
+	// key, signature or access token.
+	AliErrorAuth AliErrorKind = "auth"
+	// AliErrorResponse is a successful response that could not be decoded.
+	AliErrorResponse AliErrorKind = "response"
+	// AliErrorAPI is any other error_response or unexpected status; retrying
+	// the same request would fail the same way.
+	AliErrorAPI AliErrorKind = "api"
+)
+
+// AliExpressError is a classified failure of an AliExpress API call.
+type AliExpressError struct {
+	Kind AliErrorKind
+	// StatusCode is the HTTP status, zero for network errors.
+	StatusCode int
+	// Code, SubCode and Message are taken from an error_response body.
+	Code    string
+	SubCode string
+	Message string
+	// RetryAfter is the delay the server asked for, if any.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *AliExpressError) Error() string {
+	switch {
+	case e.Code != "":
+		msg := fmt.Sprintf("aliexpress API error %s", e.Code)
+		if e.SubCode != "" {
+			msg += "/" + e.SubCode
+		}
+		return msg + ": " + e.Message
+	case e.Err != nil:
+		return fmt.Sprintf("aliexpress %s error: %v", e.Kind, e.Err)
+	default:
+		return fmt.Sprintf("aliexpress API returned status %d: %s", e.StatusCode, e.Message)
+	}
+}
+
+func (e *AliExpressError) Unwrap() error { return e.Err }
+
+// Retryable reports whether the same call may succeed if retried.
+func (e *AliExpressError) Retryable() bool {
+	switch e.Kind {
+	case AliErrorNetwork, AliErrorServer, AliErrorRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
+// Is maps the error onto the domain error taxonomy: retryable errors match
+// domain.ErrUpstreamUnavailable and each kind its own domain error.
+func (e *AliExpressError) Is(target error) bool {
+	if target == domain.ErrUpstreamUnavailable {
+		return e.Retryable()
+	}
+	switch e.Kind {
+	case AliErrorRateLimited:
+		return target == domain.ErrUpstreamRateLimited
+	case AliErrorAuth:
+		return target == domain.ErrUpstreamAuth
+	case AliErrorResponse:
+		return target == domain.ErrUpstreamBadResponse
+	case AliErrorAPI:
+		return target == domain.ErrUpstreamInvalidRequest
+	}
+	return false
+}
+
+// unreadableAliResponse reports a successful response whose body could not be decoded.
+func unreadableAliResponse(what string, err error) *AliExpressError {
+	return &AliExpressError{
+		Kind:       AliErrorResponse,
+		StatusCode: http.StatusOK,
+		Err:        fmt.Errorf("failed to unmarshal AliExpress %s: %v", what, err),
+	}
+}
+
+// aliErrorResponse is the body AliExpress returns, with status 200, for a
+// failed call.
+type aliErrorResponse struct {
+	ErrorResponse *struct {
+		Code      json.RawMessage `json:"code"`
+		SubCode   string          `json:"sub_code"`
+		Msg       string          `json:"msg"`
+		SubMsg    string          `json:"sub_msg"`
+		RequestID string          `json:"request_id"`
+	} `json:"error_response"`
+}
+
+// parseAliErrorResponse returns the classified error_response in body, or
+// nil when body is not one.
+func parseAliErrorResponse(body []byte) *AliExpressError {
+	var resp aliErrorResponse
+	if json.Unmarshal(body, &resp) != nil || resp.ErrorResponse == nil {
+		return nil
+	}
+	er := resp.ErrorResponse
+	e := &AliExpressError{
+		Kind:       AliErrorAPI,
+		StatusCode: http.StatusOK,
+		Code:       strings.Trim(string(er.Code), `"`),
+		SubCode:    er.SubCode,
+		Message:    er.Msg,
+	}
+	if er.SubMsg != "" {
+		e.Message += " (" + er.SubMsg + ")"
+	}
+	e.Kind = classifyAliErrorCode(e.Code, e.SubCode)
+	return e
+}
+
+// aliAuthCodeTerms identify error codes rejecting the caller's credentials,
+// such as "IncompleteSignature", "InvalidAppKey" or "IllegalAccessToken".
+var aliAuthCodeTerms = []string{"signature", "appkey", "app-key", "apikey", "accesstoken", "access-token", "permission", "unauthorized", "forbidden", "whiteip"}
+
+// classifyAliErrorCode maps an error_response code to its kind. Call-limit
+// codes ("ApiCallLimit", "isv.app-call-limited", ...) are rate limits,
+// credential codes are auth failures and "isp." codes are failures on the
+// platform side.
+func classifyAliErrorCode(code, subCode string) AliErrorKind {
+	for _, c := range []string{code, subCode} {
+		lc := strings.ToLower(c)
+		switch {
+		case strings.Contains(lc, "calllimit"), strings.Contains(lc, "call-limit"), strings.Contains(lc, "frequency"):
+			return AliErrorRateLimited
+		case strings.HasPrefix(lc, "isp."):
+			return AliErrorServer
+		}
+		for _, term := range aliAuthCodeTerms {
+			if strings.Contains(lc, term) {
+				return AliErrorAuth
+			}
+		}
+	}
+	return AliErrorAPI
+}
+
+// classifyAliStatus classifies a non-200 HTTP response.
+func classifyAliStatus(resp *http.Response, body []byte) *AliExpressError {
+	e := &AliExpressError{Kind: AliErrorAPI, StatusCode: resp.StatusCode, Message: preview(body, 1000)}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		e.Kind = AliErrorRateLimited
+		if secs, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil && secs > 0 {
+			e.RetryAfter = time.Duration(secs) * time.Second
+		}
+	case resp.StatusCode >= 500:
+		e.Kind = AliErrorServer
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		e.Kind = AliErrorAuth
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		e.Message = "redirected to " + resp.Header.Get("Location")
+	}
+	return e
+}
@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// FetchHotProducts implements domain.AlibabaGateway using
+// aliexpress.affiliate.hotproduct.query.
+func (a *AlibabaHTTPGateway) FetchHotProducts(ctx context.Context, filters domain.DealsFilters, pageNo, pageSize int) (*domain.ProductPage, error) {
+	log.Printf("[AlibabaGateway] FetchHotProducts called with filters: %+v (page %d, size %d)", filters, pageNo, pageSize)
+
+	params := map[string]string{
+		"method":          "aliexpress.affiliate.hotproduct.query",
+		"fields":          aliProductFields,
+		"target_currency": "USD",
+		"target_language": "en",
+		"ship_to_country": domain.DefaultShipToCountry,
+	}
+	for k, v := range hotProductParams(filters, pageNo, pageSize) {
+		params[k] = v
+	}
+
+	body, err := a.call(ctx, a.withTracking(params))
+	if err != nil {
+		return nil, err
+	}
+	page, err := MapAliExpressHotProductResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	if page.PageSize == 0 {
+		page.PageSize = pageSize
+	}
+	return page, nil
+}
+
+// hotProductParams converts deals filters into hot-product query parameters.
+// Unset filters produce no parameter.
+func hotProductParams(filters domain.DealsFilters, pageNo, pageSize int) map[string]string {
+	params := intentParams(domain.SearchIntent{
+		CategoryID: filters.CategoryID,
+		MinPrice:   filters.MinPrice,
+		MaxPrice:   filters.MaxPrice,
+		Currency:   filters.Currency,
+	}, pageNo, pageSize)
+	if filters.PromotionName != "" {
+		params["promotion_name"] = filters.PromotionName
+	}
+	return params
+}
+
+// MapAliExpressHotProductResponse maps a hotproduct.query response. Hot
+// products carry the detail fields (gallery, shop, original price), so they
+// are mapped like product details.
+func MapAliExpressHotProductResponse(data []byte) (*domain.ProductPage, error) {
+	var resp struct {
+		AliexpressResp struct {
+			RespResult struct {
+				Result struct {
+					TotalRecordCount int `json:"total_record_count"`
+					CurrentPageNo    int `json:"current_page_no"`
+					Products         struct {
+						Product []aliProduct `json:"product"`
+					} `json:"products"`
+				} `json:"result"`
+			} `json:"resp_result"`
+		} `json:"aliexpress_affiliate_hotproduct_query_response"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, unreadableAliResponse("hot product response", err)
+	}
+
+	result := resp.AliexpressResp.RespResult.Result
+	page := &domain.ProductPage{
+		Products:         make([]*domain.Product, 0, len(result.Products.Product)),
+		TotalRecordCount: result.TotalRecordCount,
+		CurrentPageNo:    result.CurrentPageNo,
+	}
+	for _, p := range result.Products.Product {
+		page.Products = append(page.Products, mapAliProduct(p))
+	}
+	log.Println("Mapped", len(page.Products), "hot products from AliExpress response, total", strconv.Itoa(result.TotalRecordCount))
+	return page, nil
+}
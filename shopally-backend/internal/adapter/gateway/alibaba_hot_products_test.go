@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapAliExpressHotProductResponse(t *testing.T) {
+	body := `{"aliexpress_affiliate_hotproduct_query_response":{"resp_result":{"result":{
+		"total_record_count": 120, "current_page_no": 2,
+		"products": {"product": [{
+			"product_id": 1005001, "product_title": "Smart Watch",
+			"target_sale_price": "9.99", "target_original_price": "19.98", "discount": "50%",
+			"shop_id": 7, "shop_name": "Watch Store"
+		}]}}}}}`
+
+	page, err := MapAliExpressHotProductResponse([]byte(body))
+	require.NoError(t, err)
+	assert.Equal(t, 120, page.TotalRecordCount)
+	assert.Equal(t, 2, page.CurrentPageNo)
+	require.Len(t, page.Products, 1)
+
+	p := page.Products[0]
+	assert.Equal(t, "1005001", p.ID)
+	require.NotNil(t, p.OriginalPrice)
+	assert.InDelta(t, 19.98, p.OriginalPrice.USD, 1e-9)
+	assert.Equal(t, "Watch Store", p.Shop.Name)
+}
+
+func TestHotProductParams(t *testing.T) {
+	max := 25.0
+	params := hotProductParams(domain.DealsFilters{CategoryID: "44", MaxPrice: &max, Currency: "USD", PromotionName: "Hot Product"}, 3, 50)
+
+	assert.Equal(t, map[string]string{
+		"page_no":        "3",
+		"page_size":      "50",
+		"category_ids":   "44",
+		"max_sale_price": "25",
+		"promotion_name": "Hot Product",
+	}, params)
+}
@@ -7,8 +7,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
+	"math"
 	"net/http"
 	"net/url"
 	"sort"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/shopally-ai/internal/config"
 	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
 )
 
 // MapAliExpressResponseToProducts transforms the raw AliExpress API response JSON
@@ -25,48 +27,70 @@ import (
 // fields and uses sensible defaults/placeholders where mapping data is not
 // available from the upstream response.
 func MapAliExpressResponseToProducts(data []byte) ([]*domain.Product, error) {
-	type aliProduct struct {
-		AppSalePrice        string `json:"app_sale_price"`
-		OriginalPrice       string `json:"original_price"`
-		ProductDetailURL    string `json:"product_detail_url"`
-		Discount            string `json:"discount"`
-		ProductMainImageURL string `json:"product_main_image_url"`
-		TaxRate             string `json:"tax_rate"`
-		ProductID           int64  `json:"product_id"`
-		ShipToDays          string `json:"ship_to_days"`
-		EvaluateRate        string `json:"evaluate_rate"`
-		SalePrice           string `json:"sale_price"`
-		ProductTitle        string `json:"product_title"`
-
-		TargetSalePrice            string `json:"target_sale_price"`
-		TargetAppSalePrice         string `json:"target_app_sale_price"`
-		ShopName                   string `json:"shop_name"`
-		TargetSalePriceCurrency    string `json:"target_sale_price_currency"`
-		TargetAppSalePriceCurrency string `json:"target_app_sale_price_currency"`
-
-		ProductSmallImageURLs struct {
-			String []string `json:"string"`
-		} `json:"product_small_image_urls"`
-		SecondLevelCategoryName     string `json:"second_level_category_name"`
-		SecondLevelCategoryID       int64  `json:"second_level_category_id"`
-		FirstLevelCategoryID        int64  `json:"first_level_category_id"`
-		FirstLevelCategoryName      string `json:"first_level_category_name"`
-		OriginalPriceCurrency       string `json:"original_price_currency"`
-		ShopURL                     string `json:"shop_url"`
-		TargetOriginalPriceCurrency string `json:"target_original_price_currency"`
-		TargetOriginalPrice         string `json:"target_original_price"`
-		ProductVideoURL             string `json:"product_video_url"`
-		PromotionLink               string `json:"promotion_link"`
-		SKUId                       int64  `json:"sku_id"`
-		HotProductCommissionRate    string `json:"hot_product_commission_rate"`
-		ShopID                      int64  `json:"shop_id"`
-		LastestVolume               int    `json:"lastest_volume"`
-		SalePriceCurrency           string `json:"sale_price_currency"`
-		CommissionRate              string `json:"commission_rate"`
+	page, err := MapAliExpressResponseToPage(data)
+	if err != nil {
+		return []*domain.Product{}, err
 	}
+	return page.Products, nil
+}
 
+// aliProductFields lists every aliProduct field requested from the product
+// query, detail and hot product APIs.
+const aliProductFields = "product_id,product_title,product_main_image_url,product_small_image_urls,product_video_url,product_detail_url," +
+	"sale_price,app_sale_price,original_price,original_price_currency,target_sale_price,target_app_sale_price,target_original_price,target_original_price_currency," +
+	"discount,evaluate_rate,tax_rate,lastest_volume,ship_to_days,shop_id,shop_name,shop_url,sku_id," +
+	"first_level_category_id,first_level_category_name,second_level_category_id,second_level_category_name," +
+	"promotion_link,commission_rate,hot_product_commission_rate"
+
+// aliProduct is a product as returned by the affiliate product query and
+// product detail APIs.
+type aliProduct struct {
+	AppSalePrice        string `json:"app_sale_price"`
+	OriginalPrice       string `json:"original_price"`
+	ProductDetailURL    string `json:"product_detail_url"`
+	Discount            string `json:"discount"`
+	ProductMainImageURL string `json:"product_main_image_url"`
+	TaxRate             string `json:"tax_rate"`
+	ProductID           int64  `json:"product_id"`
+	ShipToDays          string `json:"ship_to_days"`
+	EvaluateRate        string `json:"evaluate_rate"`
+	SalePrice           string `json:"sale_price"`
+	ProductTitle        string `json:"product_title"`
+
+	TargetSalePrice            string `json:"target_sale_price"`
+	TargetAppSalePrice         string `json:"target_app_sale_price"`
+	ShopName                   string `json:"shop_name"`
+	TargetSalePriceCurrency    string `json:"target_sale_price_currency"`
+	TargetAppSalePriceCurrency string `json:"target_app_sale_price_currency"`
+
+	ProductSmallImageURLs struct {
+		String []string `json:"string"`
+	} `json:"product_small_image_urls"`
+	SecondLevelCategoryName     string `json:"second_level_category_name"`
+	SecondLevelCategoryID       int64  `json:"second_level_category_id"`
+	FirstLevelCategoryID        int64  `json:"first_level_category_id"`
+	FirstLevelCategoryName      string `json:"first_level_category_name"`
+	OriginalPriceCurrency       string `json:"original_price_currency"`
+	ShopURL                     string `json:"shop_url"`
+	TargetOriginalPriceCurrency string `json:"target_original_price_currency"`
+	TargetOriginalPrice         string `json:"target_original_price"`
+	ProductVideoURL             string `json:"product_video_url"`
+	PromotionLink               string `json:"promotion_link"`
+	SKUId                       int64  `json:"sku_id"`
+	HotProductCommissionRate    string `json:"hot_product_commission_rate"`
+	ShopID                      int64  `json:"shop_id"`
+	LastestVolume               int    `json:"lastest_volume"`
+	SalePriceCurrency           string `json:"sale_price_currency"`
+	CommissionRate              string `json:"commission_rate"`
+}
+
+// MapAliExpressResponseToPage is like MapAliExpressResponseToProducts but also
+// keeps the paging metadata (total_record_count, current_page_no) of the response.
+// A body that is not a product query response is returned as an
+// *AliExpressError: the classified error_response when it is one.
+func MapAliExpressResponseToPage(data []byte) (*domain.ProductPage, error) {
 	type sgResp struct {
-		AliexpressResp struct {
+		AliexpressResp *struct {
 			RespResult struct {
 				Result struct {
 					CurrentRecordCount int `json:"current_record_count"`
@@ -81,63 +105,136 @@ func MapAliExpressResponseToProducts(data []byte) ([]*domain.Product, error) {
 	}
 
 	var sg sgResp
-	err := json.Unmarshal(data, &sg)
-	if err == nil {
-		log.Printf("[AlibabaGateway] Unmarshal to sgResp succeeded. Raw product count in struct: %d", len(sg.AliexpressResp.RespResult.Result.Products.Product))
-		if len(sg.AliexpressResp.RespResult.Result.Products.Product) > 0 {
-			log.Println("[AlibabaGateway] Successfully unmarshaled with SG response structure and found products.")
-			out := make([]*domain.Product, 0, len(sg.AliexpressResp.RespResult.Result.Products.Product))
-			for _, p := range sg.AliexpressResp.RespResult.Result.Products.Product {
-				usd := parseFloatOrZero(p.TargetSalePrice)
-				if usd == 0 {
-					usd = parseFloatOrZero(p.TargetAppSalePrice)
-				}
-				if usd == 0 {
-					log.Printf("[AlibabaGateway] Warning: No explicit target USD price found for product ID %d. Falling back to SalePrice/AppSalePrice which might be in CNY.", p.ProductID)
-					usd = parseFloatOrZero(p.SalePrice)
-					if usd == 0 {
-						usd = parseFloatOrZero(p.AppSalePrice)
-					}
-				}
+	if err := json.Unmarshal(data, &sg); err != nil {
+		log.Printf("[AlibabaGateway] SG response structure unmarshaling failed: %v", err)
+		return &domain.ProductPage{Products: []*domain.Product{}}, unreadableAliResponse("product query response", err)
+	}
+	if sg.AliexpressResp == nil {
+		if apiErr := parseAliErrorResponse(data); apiErr != nil {
+			return &domain.ProductPage{Products: []*domain.Product{}}, apiErr
+		}
+		log.Printf("[AlibabaGateway] response has no product query envelope: %s", preview(data, 200))
+		return &domain.ProductPage{Products: []*domain.Product{}}, unreadableAliResponse("product query response", errors.New("missing aliexpress_affiliate_product_query_response"))
+	}
 
-				tax := parseFloatOrZero(p.TaxRate)
-				discount := parsePercentOrZero(p.Discount)
-				rating := parsePercentOrZero(p.EvaluateRate)
-
-				prod := &domain.Product{
-					ID:                strconv.FormatInt(p.ProductID, 10),
-					Title:             strings.TrimSpace(p.ProductTitle),
-					ImageURL:          strings.TrimSpace(p.ProductMainImageURL),
-					AIMatchPercentage: 0, // Placeholder
-					Price: domain.Price{
-						ETB:         0,
-						USD:         usd,
-						FXTimestamp: time.Now().UTC(),
-					},
-					ProductRating:      rating,
-					SellerScore:        0, // Placeholder
-					DeliveryEstimate:   strings.TrimSpace(p.ShipToDays),
-					Description:        "", // Not available in current API response snippet
-					CustomerHighlights: "", // Not available in current API response snippet
-					CustomerReview:     "", // Not available in current API response snippet
-					NumberSold:         p.LastestVolume,
-					SummaryBullets:     []string{},
-					DeeplinkURL:        strings.TrimSpace(p.ProductDetailURL),
-					TaxRate:            tax,
-					Discount:           discount,
-				}
-				out = append(out, prod)
-			}
-			log.Println("Mapped", len(out), "products from AliExpress SG response")
-			return out, nil
-		} else {
-			log.Println("[AlibabaGateway] Unmarshal to SG response structure succeeded, but found an empty 'product' array. This might indicate no products matched the query or a deeper API issue for this specific response.")
-			return []*domain.Product{}, nil
+	result := sg.AliexpressResp.RespResult.Result
+	page := &domain.ProductPage{
+		Products:         make([]*domain.Product, 0, len(result.Products.Product)),
+		TotalRecordCount: result.TotalRecordCount,
+		CurrentPageNo:    result.CurrentPageNo,
+	}
+	if len(result.Products.Product) == 0 {
+		log.Println("[AlibabaGateway] Unmarshal to SG response structure succeeded, but found an empty 'product' array. This might indicate no products matched the query.")
+		return page, nil
+	}
+	for _, p := range result.Products.Product {
+		page.Products = append(page.Products, mapAliProduct(p))
+	}
+	log.Println("Mapped", len(page.Products), "products from AliExpress SG response")
+	return page, nil
+}
+
+// mapAliProduct maps a product of the product query, detail or hot product
+// APIs. Optional sub-structures are left nil when the response lacks them.
+func mapAliProduct(p aliProduct) *domain.Product {
+	usd := parseFloatOrZero(p.TargetSalePrice)
+	if usd == 0 {
+		usd = parseFloatOrZero(p.TargetAppSalePrice)
+	}
+	if usd == 0 {
+		log.Printf("[AlibabaGateway] Warning: No explicit target USD price found for product ID %d. Falling back to SalePrice/AppSalePrice which might be in CNY.", p.ProductID)
+		usd = parseFloatOrZero(p.SalePrice)
+		if usd == 0 {
+			usd = parseFloatOrZero(p.AppSalePrice)
+		}
+	}
+
+	tax := parseFloatOrZero(p.TaxRate)
+	discount := parsePercentOrZero(p.Discount)
+	rating := parsePercentOrZero(p.EvaluateRate)
+	category := strings.TrimSpace(p.SecondLevelCategoryName)
+	if category == "" {
+		category = strings.TrimSpace(p.FirstLevelCategoryName)
+	}
+
+	prod := &domain.Product{
+		ID:                strconv.FormatInt(p.ProductID, 10),
+		Title:             strings.TrimSpace(p.ProductTitle),
+		ImageURL:          strings.TrimSpace(p.ProductMainImageURL),
+		AIMatchPercentage: 0, // Placeholder
+		// ETB and FXTimestamp are filled by the use case from the FX client.
+		Price: domain.Price{
+			USD: usd,
+		},
+		ProductRating:      rating,
+		SellerScore:        int(math.Round(rating)), // evaluate_rate is the only feedback score returned.
+		DeliveryEstimate:   strings.TrimSpace(p.ShipToDays),
+		Description:        "", // Text fields are written by the LLM summary;
+		CustomerHighlights: "", // the affiliate APIs return no description
+		CustomerReview:     "", // or reviews.
+		NumberSold:         p.LastestVolume,
+		SummaryBullets:     []string{},
+		DeeplinkURL:        strings.TrimSpace(p.ProductDetailURL),
+		TaxRate:            tax,
+		Discount:           discount,
+		CategoryName:       category,
+		PromotionLink:      strings.TrimSpace(p.PromotionLink),
+		CommissionRate:     parsePercentOrZero(p.CommissionRate),
+	}
+	if prod.CommissionRate == 0 {
+		prod.CommissionRate = parsePercentOrZero(p.HotProductCommissionRate)
+	}
+
+	if p.FirstLevelCategoryID != 0 {
+		prod.Categories = append(prod.Categories, domain.ProductCategory{
+			ID:    strconv.FormatInt(p.FirstLevelCategoryID, 10),
+			Name:  strings.TrimSpace(p.FirstLevelCategoryName),
+			Level: 1,
+		})
+	}
+	if p.SecondLevelCategoryID != 0 {
+		prod.Categories = append(prod.Categories, domain.ProductCategory{
+			ID:    strconv.FormatInt(p.SecondLevelCategoryID, 10),
+			Name:  strings.TrimSpace(p.SecondLevelCategoryName),
+			Level: 2,
+		})
+	}
+
+	for _, img := range p.ProductSmallImageURLs.String {
+		if img = strings.TrimSpace(img); img != "" {
+			prod.Images = append(prod.Images, img)
+		}
+	}
+	if len(prod.Images) == 0 && prod.ImageURL != "" {
+		prod.Images = []string{prod.ImageURL}
+	}
+	prod.VideoURL = strings.TrimSpace(p.ProductVideoURL)
+
+	if p.ShopID != 0 || strings.TrimSpace(p.ShopName) != "" {
+		prod.Shop = &domain.Shop{
+			Name: strings.TrimSpace(p.ShopName),
+			URL:  strings.TrimSpace(p.ShopURL),
+		}
+		if p.ShopID != 0 {
+			prod.Shop.ID = strconv.FormatInt(p.ShopID, 10)
 		}
-	} else {
-		log.Printf("[AlibabaGateway] SG response structure unmarshaling failed: %v. This is unexpected for the current API response format. Returning an empty product list.", err)
-		return []*domain.Product{}, fmt.Errorf("failed to unmarshal AliExpress response with SG structure: %v", err)
 	}
+	if p.SKUId != 0 {
+		prod.SKU = &domain.ProductSKU{ID: strconv.FormatInt(p.SKUId, 10)}
+	}
+
+	// Only a USD original price can be compared with the USD sale price.
+	original := 0.0
+	if cur := strings.ToUpper(strings.TrimSpace(p.TargetOriginalPriceCurrency)); cur == "" || cur == "USD" {
+		original = parseFloatOrZero(p.TargetOriginalPrice)
+	}
+	if original == 0 && strings.EqualFold(strings.TrimSpace(p.OriginalPriceCurrency), "USD") {
+		original = parseFloatOrZero(p.OriginalPrice)
+	}
+	if original > 0 {
+		prod.OriginalPrice = &domain.Price{USD: original}
+	}
+	return prod
 }
 
 func parseFloatOrZero(s string) float64 {
@@ -166,15 +263,46 @@ func parsePercentOrZero(s string) float64 {
 type AlibabaHTTPGateway struct {
 	client *http.Client
 	cfg    *config.Config
+
+	maxRetries int
+	baseDelay  time.Duration
+	breaker    *circuitBreaker
+	inflight   util.CallGroup[[]byte]
 }
 
-func NewAlibabaHTTPGateway(cfg *config.Config) domain.AlibabaGateway {
-	return &AlibabaHTTPGateway{
-		client: &http.Client{Timeout: 10 * time.Second},
-		cfg:    cfg,
+var _ domain.AlibabaGateway = (*AlibabaHTTPGateway)(nil)
+
+// NewAlibabaHTTPGateway creates a gateway configured from cfg. If httpClient
+// is nil, a default client is used.
+func NewAlibabaHTTPGateway(cfg *config.Config, httpClient *http.Client) *AlibabaHTTPGateway {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	a := &AlibabaHTTPGateway{
+		client:     httpClient,
+		cfg:        cfg,
+		maxRetries: DefaultAliMaxRetries,
+		baseDelay:  DefaultAliRetryBaseDelay,
+	}
+	threshold, cooldown := DefaultAliBreakerThreshold, DefaultAliBreakerCooldown
+	if cfg.Aliexpress.MaxRetries > 0 {
+		a.maxRetries = cfg.Aliexpress.MaxRetries
 	}
+	if cfg.Aliexpress.RetryBaseDelayMillis > 0 {
+		a.baseDelay = time.Duration(cfg.Aliexpress.RetryBaseDelayMillis) * time.Millisecond
+	}
+	if cfg.Aliexpress.BreakerThreshold > 0 {
+		threshold = cfg.Aliexpress.BreakerThreshold
+	}
+	if cfg.Aliexpress.BreakerCooldownSeconds > 0 {
+		cooldown = time.Duration(cfg.Aliexpress.BreakerCooldownSeconds) * time.Second
+	}
+	a.breaker = newCircuitBreaker(threshold, cooldown)
+	return a
 }
 
+// mockAliExpressResponse is served in place of an unreadable product query
+// response, in the dev profile only.
 const mockAliExpressResponse = `{
 	"aliexpress_affiliate_product_query_response": {
 		"resp_result": {
@@ -217,99 +345,60 @@ const mockAliExpressResponse = `{
 }`
 
 // FetchProducts implements usecase.AlibabaGateway.
-func (a *AlibabaHTTPGateway) FetchProducts(ctx context.Context, Keywords string, filters map[string]interface{}) ([]*domain.Product, error) {
-	ts := time.Now().UTC().UnixNano() / 1e6
-	tsStr := strconv.FormatInt(ts, 10)
-
-	log.Printf("[AlibabaGateway] FetchProducts called with query: '%s' and filters: %+v", Keywords, filters)
+func (a *AlibabaHTTPGateway) FetchProducts(ctx context.Context, intent domain.SearchIntent, pageNo, pageSize int) (*domain.ProductPage, error) {
+	log.Printf("[AlibabaGateway] FetchProducts called with intent: %+v (page %d, size %d)", intent, pageNo, pageSize)
 
 	// Initialize params with required fields and **default values**
 	params := map[string]string{
 		"method":          "aliexpress.affiliate.product.query",
-		"app_key":         a.cfg.Aliexpress.AppKey,
-		"timestamp":       tsStr,
-		"sign_method":     "sha256",
-		"keywords":        Keywords,
+		"keywords":        intent.Keywords,
 		"page_no":         "1",         // Default page number
 		"page_size":       "10",        // Default page size
 		"target_currency": "USD",       // Default currency
 		"target_language": "en",        // Default language
 		"sort":            "relevancy", // Default sort order
-
-		// Define all fields we want to receive from the API.
-		// This list should reflect all fields in `aliProduct` that you want populated.
-		"fields": "product_id,product_title,product_main_image_url,product_detail_url,sale_price,app_sale_price,original_price,discount,evaluate_rate,tax_rate,target_sale_price,target_app_sale_price,shop_name,lastest_volume,ship_to_days,first_level_category_name,second_level_category_name",
+		"fields":          aliProductFields,
 	}
 
-	// Apply overrides from the filters map
-	// For fields like min_sale_price, max_sale_price, category_ids, etc.,
-	// we only add them to params if they are explicitly provided and non-empty.
-	// This prevents sending empty string values for optional parameters, which
-	// might be interpreted differently by the API than omitting them.
-
-	// Helper function for safely setting string parameters from filters
-	setStringParam := func(key string) {
-		if v, ok := filters[key]; ok {
-			if s, ok := v.(string); ok && s != "" {
-				params[key] = s
-			}
-		}
+	// Optional parameters are only sent when set; omitting them is not the
+	// same as sending an empty value to the API.
+	for k, v := range intentParams(intent, pageNo, pageSize) {
+		params[k] = v
 	}
 
-	// Helper function for safely setting integer/float parameters from filters
-	setNumberParam := func(key string) {
-		if v, ok := filters[key]; ok {
-			switch t := v.(type) {
-			case int:
-				params[key] = strconv.Itoa(t)
-			case float64:
-				params[key] = strconv.Itoa(int(t)) // Convert float to int string
-			case string:
-				if t != "" {
-					params[key] = t
-				}
-			}
-		}
+	body, err := a.call(ctx, a.withTracking(params))
+	if err != nil {
+		return nil, err
 	}
 
-	setFloatParam := func(key string) {
-		if v, ok := filters[key]; ok {
-			switch t := v.(type) {
-			case float64:
-				// Use -1 for precision to represent the smallest number of digits
-				// necessary to accurately represent value
-				params[key] = strconv.FormatFloat(t, 'f', -1, 64)
-			case string:
-				if t != "" {
-					params[key] = t
-				}
-			}
+	page, err := MapAliExpressResponseToPage(body)
+	if err != nil {
+		if !a.cfg.IsDev() {
+			return nil, err
 		}
+		log.Printf("[AlibabaGateway] mapping error from real API response: %v. Serving mock products (%s profile).", err, config.ProfileDev)
+		page, err = MapAliExpressResponseToPage([]byte(mockAliExpressResponse))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if page.PageSize == 0 {
+		page.PageSize, _ = strconv.Atoi(params["page_size"])
 	}
+	return page, nil
+}
 
-	// Override defaults with values from the filters map
-	setNumberParam("page_no")
-	setNumberParam("page_size")
-	setStringParam("category_ids")
-	setFloatParam("min_sale_price") // Use setFloatParam for prices
-	setFloatParam("max_sale_price") // Use setFloatParam for prices
-	setStringParam("platform_product_type")
-	setStringParam("sort")
-	setStringParam("target_currency")
-	setStringParam("target_language")
-	setStringParam("tracking_id")
-	setStringParam("promotion_name")
-	setStringParam("ship_to_country")
-	setNumberParam("delivery_days")
+// do signs params with the common system parameters, sends the request
+// and returns the raw response body of a successful call. Failures are
+// returned as *AliExpressError.
+func (a *AlibabaHTTPGateway) do(ctx context.Context, params map[string]string) ([]byte, error) {
+	params["app_key"] = a.cfg.Aliexpress.AppKey
+	params["timestamp"] = strconv.FormatInt(time.Now().UTC().UnixNano()/1e6, 10)
+	params["sign_method"] = "sha256"
 
 	// Log final params for debugging
 	log.Printf("[AlibabaGateway] Final API params: %+v", params)
 
-	// The 'fields' parameter is critical for our mapper. It's best to control
-	// it internally to ensure all expected fields for `aliProduct` are always requested.
-	// If the user *must* override it, a more complex merge/validation logic would be needed.
-	// For now, we prioritize our hardcoded list for reliability.
-
 	sign := computeAliSign(params, a.cfg.Aliexpress.AppSecret)
 	params["sign"] = sign
 
@@ -341,31 +430,26 @@ func (a *AlibabaHTTPGateway) FetchProducts(ctx context.Context, Keywords string,
 	resp, err := a.client.Do(req)
 	if err != nil {
 		log.Printf("[AlibabaGateway] http request error: %v", err)
-		return nil, err
+		return nil, &AliExpressError{Kind: AliErrorNetwork, Err: err}
 	}
 	defer resp.Body.Close()
 
 	respBody := new(bytes.Buffer)
-	_, _ = respBody.ReadFrom(resp.Body)
-	log.Printf("[AlibabaGateway] response status=%d body_preview=%s", resp.StatusCode, preview(respBody.Bytes(), 800))
-
-	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
-		loc := resp.Header.Get("Location")
-		log.Printf("[AlibabaGateway] redirect detected: status=%d location=%s", resp.StatusCode, loc)
-		return nil, fmt.Errorf("aliexpress API redirected: status=%d location=%s", resp.StatusCode, loc)
+	if _, err := respBody.ReadFrom(resp.Body); err != nil {
+		log.Printf("[AlibabaGateway] reading response body failed: %v", err)
+		return nil, &AliExpressError{Kind: AliErrorNetwork, StatusCode: resp.StatusCode, Err: err}
 	}
+	log.Printf("[AlibabaGateway] response status=%d body_preview=%s", resp.StatusCode, preview(respBody.Bytes(), 800))
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[AlibabaGateway] non-200 response: %d body: %s", resp.StatusCode, preview(respBody.Bytes(), 1000))
-		return nil, fmt.Errorf("aliexpress API returned status %d: %s", resp.StatusCode, preview(respBody.Bytes(), 1000))
+		log.Printf("[AlibabaGateway] non-200 response: %d location=%q body: %s", resp.StatusCode, resp.Header.Get("Location"), preview(respBody.Bytes(), 1000))
+		return nil, classifyAliStatus(resp, respBody.Bytes())
 	}
-
-	prods, err := MapAliExpressResponseToProducts(respBody.Bytes())
-	if err != nil {
-		log.Printf("[AlibabaGateway] mapping error from real API response: %v. Attempting mock fallback for development.", err)
-		return MapAliExpressResponseToProducts([]byte(mockAliExpressResponse))
+	if apiErr := parseAliErrorResponse(respBody.Bytes()); apiErr != nil {
+		log.Printf("[AlibabaGateway] error_response: kind=%s %v", apiErr.Kind, apiErr)
+		return nil, apiErr
 	}
-	return prods, nil
+	return respBody.Bytes(), nil
 }
 
 // computeAliSign computes the signature expected by the AliExpress affiliate API.
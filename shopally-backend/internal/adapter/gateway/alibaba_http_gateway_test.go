@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/shopally-ai/pkg/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -77,12 +79,74 @@ func TestMapAliExpressResponseToProducts(t *testing.T) {
 		assert.InDelta(t, 0.1, p.TaxRate, 0.0001)
 		assert.InDelta(t, 50.0, p.Discount, 0.0001)
 		assert.InDelta(t, 92.1, p.ProductRating, 0.0001)
+		assert.Equal(t, 92, p.SellerScore)
 		assert.Equal(t, 5, p.NumberSold)
 	})
 
+	t.Run("full product payload", func(t *testing.T) {
+		products, err := MapAliExpressResponseToProducts([]byte(`{"aliexpress_affiliate_product_query_response":{"resp_result":{"result":{"products":{"product":[{
+			"product_id": 1005001,
+			"product_title": "Smart Watch",
+			"product_main_image_url": "https://img.test/main.jpg",
+			"product_small_image_urls": {"string": ["https://img.test/1.jpg", " ", "https://img.test/2.jpg"]},
+			"product_video_url": "https://video.test/1.mp4",
+			"target_sale_price": "20.00",
+			"target_original_price": "40.00",
+			"target_original_price_currency": "USD",
+			"shop_id": 2001,
+			"shop_name": "Watch Store",
+			"shop_url": "https://shop.test/2001",
+			"sku_id": 3001,
+			"first_level_category_id": 44,
+			"first_level_category_name": "Consumer Electronics",
+			"second_level_category_id": 200,
+			"second_level_category_name": "Smart Watches",
+			"commission_rate": "8.0%"
+		}]}}}}}`))
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+
+		p := products[0]
+		assert.Equal(t, []string{"https://img.test/1.jpg", "https://img.test/2.jpg"}, p.Images)
+		assert.Equal(t, "https://video.test/1.mp4", p.VideoURL)
+		require.NotNil(t, p.OriginalPrice)
+		assert.InDelta(t, 40.0, p.OriginalPrice.USD, 0.0001)
+		assert.Equal(t, &domain.Shop{ID: "2001", Name: "Watch Store", URL: "https://shop.test/2001"}, p.Shop)
+		assert.Equal(t, &domain.ProductSKU{ID: "3001"}, p.SKU)
+		assert.Equal(t, "Smart Watches", p.CategoryName)
+		assert.Equal(t, []domain.ProductCategory{
+			{ID: "44", Name: "Consumer Electronics", Level: 1},
+			{ID: "200", Name: "Smart Watches", Level: 2},
+		}, p.Categories)
+		assert.InDelta(t, 8.0, p.CommissionRate, 0.0001)
+	})
+
+	t.Run("missing optional fields are omitted from JSON", func(t *testing.T) {
+		products, err := MapAliExpressResponseToProducts([]byte(`{"aliexpress_affiliate_product_query_response":{"resp_result":{"result":{"products":{"product":[{
+			"product_id": 1, "product_title": "Plain", "target_sale_price": "5.00", "original_price": "30.00", "original_price_currency": "CNY"
+		}]}}}}}`))
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Nil(t, products[0].OriginalPrice, "a non-USD original price is not comparable")
+
+		raw, err := json.Marshal(products[0])
+		require.NoError(t, err)
+		for _, key := range []string{"images", "videoUrl", "shop", "sku", "categories", "originalPrice"} {
+			assert.NotContains(t, string(raw), `"`+key+`"`)
+		}
+	})
+
 	t.Run("valid response with empty product list", func(t *testing.T) {
 		products, err := MapAliExpressResponseToProducts([]byte(mockAliExpressResponseEmpty))
 		require.NoError(t, err)
 		assert.Empty(t, products)
 	})
 }
+
+func TestMapAliExpressResponseToPage(t *testing.T) {
+	page, err := MapAliExpressResponseToPage([]byte(mockAliExpressResponseValid))
+	require.NoError(t, err)
+	assert.Len(t, page.Products, 1)
+	assert.Equal(t, 1, page.TotalRecordCount)
+	assert.Equal(t, 1, page.CurrentPageNo)
+}
@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"strconv"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// aliSortParams maps client sort options to the AliExpress sort parameter.
+// Options missing here keep the API's default relevance order.
+var aliSortParams = map[string]string{
+	"cheapest":    "SALE_PRICE_ASC",
+	"price_asc":   "SALE_PRICE_ASC",
+	"price_desc":  "SALE_PRICE_DESC",
+	"most_sold":   "LAST_VOLUME_DESC",
+	"orders_desc": "LAST_VOLUME_DESC",
+}
+
+// aliDeliveryDays are the delivery_days values the product query accepts.
+var aliDeliveryDays = []int{3, 5, 7, 10}
+
+// intentParams converts a validated intent into AliExpress query parameters.
+// Unset fields produce no parameter.
+func intentParams(intent domain.SearchIntent, pageNo, pageSize int) map[string]string {
+	params := map[string]string{}
+	if pageNo > 0 {
+		params["page_no"] = strconv.Itoa(pageNo)
+	}
+	if pageSize > 0 {
+		params["page_size"] = strconv.Itoa(pageSize)
+	}
+	if intent.CategoryID != "" {
+		params["category_ids"] = intent.CategoryID
+	}
+	// Prices are only sent in USD, the query's target currency.
+	if intent.Currency == "" || intent.Currency == "USD" {
+		if intent.MinPrice != nil {
+			params["min_sale_price"] = strconv.FormatFloat(*intent.MinPrice, 'f', -1, 64)
+		}
+		if intent.MaxPrice != nil {
+			params["max_sale_price"] = strconv.FormatFloat(*intent.MaxPrice, 'f', -1, 64)
+		}
+	}
+	if sort := aliSortParams[intent.Sort]; sort != "" {
+		params["sort"] = sort
+	}
+	if intent.ShipToCountry != "" {
+		params["ship_to_country"] = intent.ShipToCountry
+	}
+	if intent.MaxDeliveryDays != nil {
+		// Round up to the nearest supported bucket; longer bounds are enforced
+		// locally by the search use case instead.
+		for _, d := range aliDeliveryDays {
+			if *intent.MaxDeliveryDays <= d {
+				params["delivery_days"] = strconv.Itoa(d)
+				break
+			}
+		}
+	}
+	return params
+}
@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntentParams(t *testing.T) {
+	minPrice, maxPrice, days := 10.5, 120.0, 6
+	params := intentParams(domain.SearchIntent{
+		Keywords:        "phone",
+		MinPrice:        &minPrice,
+		MaxPrice:        &maxPrice,
+		Currency:        "USD",
+		CategoryID:      "44,509",
+		MaxDeliveryDays: &days,
+		Sort:            "cheapest",
+		ShipToCountry:   "ET",
+	}, 2, 20)
+
+	assert.Equal(t, map[string]string{
+		"page_no":         "2",
+		"page_size":       "20",
+		"category_ids":    "44,509",
+		"min_sale_price":  "10.5",
+		"max_sale_price":  "120",
+		"sort":            "SALE_PRICE_ASC",
+		"ship_to_country": "ET",
+		"delivery_days":   "7",
+	}, params)
+}
+
+func TestIntentParams_OmitsUnsupportedValues(t *testing.T) {
+	maxPrice, days := 5000.0, 20
+	params := intentParams(domain.SearchIntent{
+		MaxPrice:        &maxPrice,
+		Currency:        "ETB",
+		MaxDeliveryDays: &days,
+		Sort:            "fastest",
+	}, 0, 0)
+
+	assert.Empty(t, params, "ETB prices, local-only sorts and long delivery bounds must not be sent")
+}
+
+func TestLLMIntent_LenientDecoding(t *testing.T) {
+	raw := `{"keywords":" gaming laptop ","min_sale_price":"1,000","max_sale_price":null,
+		"category_ids":7,"delivery_days":4.5,"ship_to_country":"ET","is_etb":false}`
+
+	var parsed llmIntent
+	require.NoError(t, json.Unmarshal([]byte(raw), &parsed))
+	intent := parsed.toSearchIntent()
+
+	assert.Equal(t, "gaming laptop", intent.Keywords)
+	require.NotNil(t, intent.MinPrice)
+	assert.Equal(t, 1000.0, *intent.MinPrice)
+	assert.Nil(t, intent.MaxPrice)
+	assert.Equal(t, "7", intent.CategoryID)
+	require.NotNil(t, intent.MaxDeliveryDays)
+	assert.Equal(t, 5, *intent.MaxDeliveryDays)
+	assert.Equal(t, "USD", intent.Currency)
+}
+
+func TestLLMIntent_DefaultsToETB(t *testing.T) {
+	var parsed llmIntent
+	require.NoError(t, json.Unmarshal([]byte(`{"keywords":"shoes","max_sale_price":"cheap"}`), &parsed))
+	intent := parsed.toSearchIntent()
+
+	assert.Equal(t, "ETB", intent.Currency)
+	assert.Nil(t, intent.MaxPrice, "non-numeric prices are ignored")
+}
+
+func TestLLMIntentDelta_OnlySetsGivenFields(t *testing.T) {
+	var parsed llmIntentDelta
+	require.NoError(t, json.Unmarshal([]byte(`{"keywords":null,"max_sale_price":"2000","sort":"cheapest","clear":["maxDeliveryDays"]}`), &parsed))
+	delta := parsed.toIntentDelta()
+
+	assert.Empty(t, delta.Set.Keywords)
+	require.NotNil(t, delta.Set.MaxPrice)
+	assert.Equal(t, 2000.0, *delta.Set.MaxPrice)
+	assert.Equal(t, "ETB", delta.Set.Currency)
+	assert.Equal(t, "cheapest", delta.Set.Sort)
+	assert.Empty(t, delta.Set.ShipToCountry)
+	assert.Equal(t, []string{"maxDeliveryDays"}, delta.Clear)
+}
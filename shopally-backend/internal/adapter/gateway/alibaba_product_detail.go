@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// FetchProductDetail implements domain.AlibabaGateway using
+// aliexpress.affiliate.productdetail.get.
+func (a *AlibabaHTTPGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	log.Printf("[AlibabaGateway] FetchProductDetail called for product %s", productID)
+
+	params := map[string]string{
+		"method":          "aliexpress.affiliate.productdetail.get",
+		"product_ids":     productID,
+		"fields":          aliProductFields,
+		"target_currency": "USD",
+		"target_language": "en",
+		"country":         domain.DefaultShipToCountry,
+	}
+
+	body, err := a.call(ctx, a.withTracking(params))
+	if err != nil {
+		return nil, err
+	}
+	return MapAliExpressDetailResponse(body, productID)
+}
+
+// MapAliExpressDetailResponse maps a productdetail.get response to the
+// product with the given ID. It returns domain.ErrProductNotFound when the
+// response does not contain that product.
+func MapAliExpressDetailResponse(data []byte, productID string) (*domain.Product, error) {
+	var resp struct {
+		AliexpressResp struct {
+			RespResult struct {
+				Result struct {
+					CurrentRecordCount int `json:"current_record_count"`
+					Products           struct {
+						Product []aliProduct `json:"product"`
+					} `json:"products"`
+				} `json:"result"`
+			} `json:"resp_result"`
+		} `json:"aliexpress_affiliate_productdetail_get_response"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, unreadableAliResponse("product detail response", err)
+	}
+
+	for _, p := range resp.AliexpressResp.RespResult.Result.Products.Product {
+		if strconv.FormatInt(p.ProductID, 10) == productID {
+			return mapAliProduct(p), nil
+		}
+	}
+	log.Printf("[AlibabaGateway] product detail response has no product %s", productID)
+	return nil, domain.ErrProductNotFound
+}
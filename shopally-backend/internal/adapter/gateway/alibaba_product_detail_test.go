@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockAliExpressDetailResponse = `{
+    "aliexpress_affiliate_productdetail_get_response": {
+        "resp_result": {
+            "resp_code": 200,
+            "resp_msg": "Call succeeds",
+            "result": {
+                "current_record_count": 1,
+                "products": {
+                    "product": [
+                        {
+                            "product_id": 1005006123456789,
+                            "product_title": "TWS Wireless Earbuds ",
+                            "product_main_image_url": "https://example.com/main.jpg",
+                            "product_small_image_urls": {"string": ["https://example.com/1.jpg", " ", "https://example.com/2.jpg"]},
+                            "product_video_url": "https://example.com/video.mp4",
+                            "product_detail_url": "https://www.aliexpress.com/item/1005006123456789.html",
+                            "target_sale_price": "12.50",
+                            "target_original_price": "25.00",
+                            "target_original_price_currency": "USD",
+                            "original_price": "180.00",
+                            "original_price_currency": "CNY",
+                            "discount": "50%",
+                            "evaluate_rate": "95.4%",
+                            "lastest_volume": 840,
+                            "ship_to_days": "15-30 days",
+                            "shop_id": 912345,
+                            "shop_name": "Audio Store",
+                            "shop_url": "https://www.aliexpress.com/store/912345",
+                            "first_level_category_id": 44,
+                            "first_level_category_name": "Consumer Electronics",
+                            "second_level_category_id": 63705,
+                            "second_level_category_name": "Earphones & Headphones"
+                        }
+                    ]
+                }
+            }
+        }
+    }
+}`
+
+func TestMapAliExpressDetailResponse(t *testing.T) {
+	t.Run("maps the full field set", func(t *testing.T) {
+		p, err := MapAliExpressDetailResponse([]byte(mockAliExpressDetailResponse), "1005006123456789")
+		require.NoError(t, err)
+
+		assert.Equal(t, "TWS Wireless Earbuds", p.Title)
+		assert.InDelta(t, 12.5, p.Price.USD, 0.0001)
+		require.NotNil(t, p.OriginalPrice)
+		assert.InDelta(t, 25.0, p.OriginalPrice.USD, 0.0001)
+		assert.Equal(t, []string{"https://example.com/1.jpg", "https://example.com/2.jpg"}, p.Images)
+		assert.Equal(t, "https://example.com/video.mp4", p.VideoURL)
+		assert.Equal(t, &domain.Shop{ID: "912345", Name: "Audio Store", URL: "https://www.aliexpress.com/store/912345"}, p.Shop)
+		assert.Equal(t, []domain.ProductCategory{
+			{ID: "44", Name: "Consumer Electronics", Level: 1},
+			{ID: "63705", Name: "Earphones & Headphones", Level: 2},
+		}, p.Categories)
+		assert.Equal(t, "Earphones & Headphones", p.CategoryName)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		_, err := MapAliExpressDetailResponse([]byte(mockAliExpressDetailResponse), "42")
+		assert.True(t, errors.Is(err, domain.ErrProductNotFound))
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := MapAliExpressDetailResponse([]byte(`{`), "42")
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, domain.ErrProductNotFound))
+	})
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+const (
+	// DefaultAliMaxRetries is how often a retryable call is retried.
+	DefaultAliMaxRetries = 2
+	// DefaultAliRetryBaseDelay is the backoff before the first retry; it
+	// doubles with every further retry.
+	DefaultAliRetryBaseDelay = 200 * time.Millisecond
+	// DefaultAliBreakerThreshold is the number of consecutive failed calls
+	// that opens the circuit breaker.
+	DefaultAliBreakerThreshold = 5
+	// DefaultAliBreakerCooldown is how long the breaker stays open before a
+	// probe call is let through.
+	DefaultAliBreakerCooldown = 30 * time.Second
+
+	// maxAliRetryDelay caps a single backoff, including a server's Retry-After.
+	maxAliRetryDelay = 5 * time.Second
+)
+
+// errCircuitOpen is returned without calling AliExpress while the breaker is open.
+var errCircuitOpen = fmt.Errorf("aliexpress circuit breaker is open: %w", domain.ErrUpstreamUnavailable)
+
+// call is callWithRetries shared by concurrent identical calls: callers
+// passing the same params wait for one upstream call, which is cancelled only
+// when all of them have given up.
+func (a *AlibabaHTTPGateway) call(ctx context.Context, params map[string]string) ([]byte, error) {
+	body, shared, err := a.inflight.Do(ctx, aliCallKey(params), func(ctx context.Context) ([]byte, error) {
+		return a.callWithRetries(ctx, params)
+	})
+	if shared {
+		log.Printf("[AlibabaGateway] shared in-flight %s call", params["method"])
+	}
+	return body, err
+}
+
+// aliCallKey identifies a call by its sorted params.
+func aliCallKey(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(params[k])
+		b.WriteByte('\x1f')
+	}
+	return b.String()
+}
+
+// callWithRetries is do with retries and the circuit breaker. Network, 5xx
+// and rate-limit failures are retried with jittered exponential backoff; a
+// call that still fails after its retries counts once towards opening the
+// breaker. Other errors are returned at once and, since the upstream
+// answered, reset the failure streak like a success. Errors returned for an
+// unavailable upstream match domain.ErrUpstreamUnavailable.
+func (a *AlibabaHTTPGateway) callWithRetries(ctx context.Context, params map[string]string) ([]byte, error) {
+	if !a.breaker.allow() {
+		log.Printf("[AlibabaGateway] circuit open, not calling %s", params["method"])
+		return nil, errCircuitOpen
+	}
+
+	body, err := a.retry(ctx, params)
+	var aliErr *AliExpressError
+	switch {
+	case err == nil:
+		a.breaker.success()
+	case ctx.Err() != nil:
+		a.breaker.release()
+	case !errors.As(err, &aliErr) || !aliErr.Retryable():
+		// The upstream answered; the request itself was at fault.
+		a.breaker.success()
+	default:
+		a.breaker.failure()
+	}
+	return body, err
+}
+
+// retry calls do until it succeeds, fails with an error that is not
+// retryable or has been retried maxRetries times.
+func (a *AlibabaHTTPGateway) retry(ctx context.Context, params map[string]string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		// do adds the signature and timestamp, which are redone per attempt.
+		attemptParams := make(map[string]string, len(params)+4)
+		for k, v := range params {
+			attemptParams[k] = v
+		}
+		body, err := a.do(ctx, attemptParams)
+		var aliErr *AliExpressError
+		if err == nil || ctx.Err() != nil || !errors.As(err, &aliErr) || !aliErr.Retryable() || attempt >= a.maxRetries {
+			return body, err
+		}
+
+		delay := a.backoff(attempt, aliErr.RetryAfter)
+		log.Printf("[AlibabaGateway] %s failed (%s), retry %d/%d in %s", params["method"], aliErr.Kind, attempt+1, a.maxRetries, delay)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// backoff returns the delay before retry attempt+1: a random duration in
+// [d/2, d) for d = baseDelay * 2^attempt, or the server's Retry-After when longer.
+func (a *AlibabaHTTPGateway) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	d := a.baseDelay << attempt
+	if d <= 0 || d > maxAliRetryDelay {
+		d = maxAliRetryDelay
+	}
+	if half := d / 2; half > 0 {
+		d = half + rand.N(half)
+	}
+	if retryAfter > d {
+		d = retryAfter
+	}
+	if d > maxAliRetryDelay {
+		d = maxAliRetryDelay
+	}
+	return d
+}
+
+// CircuitStatus reports the state of the breaker guarding AliExpress calls.
+func (a *AlibabaHTTPGateway) CircuitStatus() CircuitStatus {
+	return a.breaker.status()
+}
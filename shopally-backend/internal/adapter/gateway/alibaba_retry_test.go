@@ -0,0 +1,238 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAliGateway points a gateway at a server answering with respond and
+// counts the requests it receives.
+func newTestAliGateway(t *testing.T, respond func(w http.ResponseWriter, n int32)) (*AlibabaHTTPGateway, *int32) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(w, atomic.AddInt32(&hits, 1))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{}
+	cfg.Aliexpress.BaseURL = srv.URL
+	cfg.Aliexpress.RetryBaseDelayMillis = 1
+	return NewAlibabaHTTPGateway(cfg, nil), &hits
+}
+
+func TestAlibabaCall_RetriesServerErrors(t *testing.T) {
+	g, hits := newTestAliGateway(t, func(w http.ResponseWriter, n int32) {
+		if n <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(mockAliExpressResponseValid))
+	})
+
+	page, err := g.FetchProducts(context.Background(), domain.SearchIntent{Keywords: "dress"}, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, page.Products, 1)
+	assert.EqualValues(t, 3, atomic.LoadInt32(hits))
+	assert.Equal(t, CircuitClosed, g.CircuitStatus().State)
+}
+
+func TestAlibabaCall_DoesNotRetryAPIErrors(t *testing.T) {
+	g, hits := newTestAliGateway(t, func(w http.ResponseWriter, n int32) {
+		_, _ = w.Write([]byte(`{"error_response":{"code":"InvalidParameter","msg":"page_size is invalid","request_id":"x"}}`))
+	})
+
+	_, err := g.FetchProducts(context.Background(), domain.SearchIntent{Keywords: "dress"}, 1, 10)
+	var aliErr *AliExpressError
+	require.ErrorAs(t, err, &aliErr)
+	assert.Equal(t, AliErrorAPI, aliErr.Kind)
+	assert.Equal(t, "InvalidParameter", aliErr.Code)
+	assert.False(t, errors.Is(err, domain.ErrUpstreamUnavailable))
+	assert.EqualValues(t, 1, atomic.LoadInt32(hits))
+}
+
+func TestClassifyAliErrors(t *testing.T) {
+	for body, want := range map[string]AliErrorKind{
+		`{"error_response":{"code":"ApiCallLimit","msg":"too many calls"}}`:                       AliErrorRateLimited,
+		`{"error_response":{"code":7,"sub_code":"isv.app-call-limited","msg":"limited"}}`:         AliErrorRateLimited,
+		`{"error_response":{"code":"15","sub_code":"isp.service-unavailable","msg":"try later"}}`: AliErrorServer,
+		`{"error_response":{"code":"IncompleteSignature","msg":"bad sign"}}`:                      AliErrorAuth,
+		`{"error_response":{"code":"InvalidParameter","msg":"page_size is invalid"}}`:             AliErrorAPI,
+	} {
+		e := parseAliErrorResponse([]byte(body))
+		require.NotNil(t, e, body)
+		assert.Equal(t, want, e.Kind, body)
+	}
+	assert.Nil(t, parseAliErrorResponse([]byte(mockAliExpressResponseValid)))
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"2"}}}
+	e := classifyAliStatus(resp, nil)
+	assert.Equal(t, AliErrorRateLimited, e.Kind)
+	assert.Equal(t, 2*time.Second, e.RetryAfter)
+	assert.True(t, errors.Is(e, domain.ErrUpstreamUnavailable))
+}
+
+func TestAlibabaCall_BreakerOpensAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	g, hits := newTestAliGateway(t, func(w http.ResponseWriter, n int32) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(mockAliExpressResponseValid))
+	})
+	g.maxRetries = 0
+	g.breaker = newCircuitBreaker(2, time.Minute)
+	now := time.Now()
+	g.breaker.now = func() time.Time { return now }
+
+	intent := domain.SearchIntent{Keywords: "dress"}
+	for i := 0; i < 2; i++ {
+		_, err := g.FetchProducts(context.Background(), intent, 1, 10)
+		require.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	}
+	_, err := g.FetchProducts(context.Background(), intent, 1, 10)
+	assert.ErrorIs(t, err, errCircuitOpen)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.EqualValues(t, 2, atomic.LoadInt32(hits), "an open breaker must not call upstream")
+	assert.Equal(t, CircuitOpen, g.CircuitStatus().State)
+
+	// After the cooldown a single probe is let through and closes the breaker.
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+	_, err = g.FetchProducts(context.Background(), intent, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, CircuitStatus{State: CircuitClosed}, g.CircuitStatus())
+}
+
+func TestAlibabaCall_BreakerCountsCallsNotAttempts(t *testing.T) {
+	var apiError atomic.Bool
+	g, hits := newTestAliGateway(t, func(w http.ResponseWriter, n int32) {
+		if apiError.Load() {
+			_, _ = w.Write([]byte(`{"error_response":{"code":"InvalidParameter","msg":"page_size is invalid"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	g.breaker = newCircuitBreaker(2, time.Minute)
+
+	// One call and its two retries are a single failure.
+	intent := domain.SearchIntent{Keywords: "dress"}
+	_, err := g.FetchProducts(context.Background(), intent, 1, 10)
+	require.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.EqualValues(t, 3, atomic.LoadInt32(hits))
+	assert.Equal(t, CircuitStatus{State: CircuitClosed, ConsecutiveFailures: 1}, g.CircuitStatus())
+
+	// An error_response proves the upstream is answering and ends the streak.
+	apiError.Store(true)
+	_, err = g.FetchProducts(context.Background(), intent, 1, 10)
+	require.Error(t, err)
+	assert.Equal(t, CircuitStatus{State: CircuitClosed}, g.CircuitStatus())
+
+	// Two failed calls in a row open it.
+	apiError.Store(false)
+	for i := 0; i < 2; i++ {
+		_, err = g.FetchProducts(context.Background(), intent, 1, 10)
+		require.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	}
+	assert.Equal(t, CircuitOpen, g.CircuitStatus().State)
+}
+
+func TestAlibabaBackoff(t *testing.T) {
+	g := NewAlibabaHTTPGateway(&config.Config{}, nil)
+	for i := 0; i < 20; i++ {
+		d := g.backoff(1, 0)
+		assert.GreaterOrEqual(t, d, DefaultAliRetryBaseDelay)
+		assert.Less(t, d, 2*DefaultAliRetryBaseDelay)
+	}
+	assert.Equal(t, 3*time.Second, g.backoff(0, 3*time.Second), "Retry-After wins when longer")
+	assert.LessOrEqual(t, g.backoff(40, time.Hour), maxAliRetryDelay)
+}
+
+func TestFetchProducts_UnreadableResponse(t *testing.T) {
+	g, _ := newTestAliGateway(t, func(w http.ResponseWriter, n int32) {
+		_, _ = w.Write([]byte(`{"unexpected":true}`))
+	})
+	intent := domain.SearchIntent{Keywords: "dress"}
+
+	_, err := g.FetchProducts(context.Background(), intent, 1, 10)
+	assert.ErrorIs(t, err, domain.ErrUpstreamBadResponse)
+	assert.False(t, errors.Is(err, domain.ErrUpstreamUnavailable))
+
+	// Only the dev profile substitutes mock products.
+	g.cfg.Profile = config.ProfileDev
+	page, err := g.FetchProducts(context.Background(), intent, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, page.Products, 1)
+	assert.Equal(t, "33006951782", page.Products[0].ID)
+}
+
+func TestAliExpressError_DomainTaxonomy(t *testing.T) {
+	_, err := MapAliExpressResponseToPage([]byte(`{"error_response":{"code":"IllegalAccessToken","msg":"token expired"}}`))
+	assert.ErrorIs(t, err, domain.ErrUpstreamAuth)
+
+	for kind, want := range map[AliErrorKind][]error{
+		AliErrorNetwork:     {domain.ErrUpstreamUnavailable},
+		AliErrorServer:      {domain.ErrUpstreamUnavailable},
+		AliErrorRateLimited: {domain.ErrUpstreamUnavailable, domain.ErrUpstreamRateLimited},
+		AliErrorAuth:        {domain.ErrUpstreamAuth},
+		AliErrorResponse:    {domain.ErrUpstreamBadResponse},
+		AliErrorAPI:         {domain.ErrUpstreamInvalidRequest},
+	} {
+		e := &AliExpressError{Kind: kind}
+		for _, target := range []error{domain.ErrUpstreamUnavailable, domain.ErrUpstreamRateLimited, domain.ErrUpstreamAuth, domain.ErrUpstreamBadResponse, domain.ErrUpstreamInvalidRequest} {
+			assert.Equal(t, containsErr(want, target), errors.Is(e, target), "%s is %v", kind, target)
+		}
+	}
+}
+
+func containsErr(errs []error, target error) bool {
+	for _, e := range errs {
+		if e == target {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAlibabaCall_CoalescesIdenticalCalls(t *testing.T) {
+	release := make(chan struct{})
+	g, hits := newTestAliGateway(t, func(w http.ResponseWriter, n int32) {
+		<-release
+		_, _ = w.Write([]byte(mockAliExpressResponseValid))
+	})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			page, err := g.FetchProducts(context.Background(), domain.SearchIntent{Keywords: "dress"}, 1, 10)
+			assert.NoError(t, err)
+			assert.Len(t, page.Products, 1)
+		}()
+	}
+	for atomic.LoadInt32(hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(hits))
+
+	// Different params are separate calls.
+	_, err := g.FetchProducts(context.Background(), domain.SearchIntent{Keywords: "dress"}, 2, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(hits))
+}
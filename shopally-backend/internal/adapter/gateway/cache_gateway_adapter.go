@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// CacheGatewayAdapter exposes an ICachePort (e.g. RedisCache) as a domain.CacheGateway.
+// Misses are reported as domain.ErrCacheMiss and non-string values are stored as JSON.
+type CacheGatewayAdapter struct {
+	port domain.ICachePort
+}
+
+// NewCacheGateway wraps the given cache port.
+func NewCacheGateway(port domain.ICachePort) *CacheGatewayAdapter {
+	return &CacheGatewayAdapter{port: port}
+}
+
+// Get returns the cached value or domain.ErrCacheMiss when the key is absent.
+func (a *CacheGatewayAdapter) Get(ctx context.Context, key string) (string, error) {
+	val, ok, err := a.port.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", domain.ErrCacheMiss
+	}
+	return val, nil
+}
+
+// Set stores strings and byte slices as-is and JSON-encodes anything else.
+func (a *CacheGatewayAdapter) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		s = string(b)
+	}
+	return a.port.Set(ctx, key, s, expiration)
+}
+
+var _ domain.CacheGateway = (*CacheGatewayAdapter)(nil)
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheGatewayAdapterSuite struct {
+	suite.Suite
+	ctx  context.Context
+	port *mocks.ICachePort
+	a    *CacheGatewayAdapter
+}
+
+func (s *CacheGatewayAdapterSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.port = mocks.NewICachePort(s.T())
+	s.a = NewCacheGateway(s.port)
+}
+
+func (s *CacheGatewayAdapterSuite) TestGetMissReturnsErrCacheMiss() {
+	s.port.On("Get", s.ctx, "k").Return("", false, nil).Once()
+	_, err := s.a.Get(s.ctx, "k")
+	s.ErrorIs(err, domain.ErrCacheMiss)
+}
+
+func (s *CacheGatewayAdapterSuite) TestGetPropagatesError() {
+	s.port.On("Get", s.ctx, "k").Return("", false, errors.New("redis down")).Once()
+	_, err := s.a.Get(s.ctx, "k")
+	s.Error(err)
+	s.NotErrorIs(err, domain.ErrCacheMiss)
+}
+
+func (s *CacheGatewayAdapterSuite) TestSetEncodesStructsAsJSON() {
+	s.port.On("Set", s.ctx, "k", `{"a":1}`, time.Minute).Return(nil).Once()
+	s.Require().NoError(s.a.Set(s.ctx, "k", map[string]int{"a": 1}, time.Minute))
+}
+
+func (s *CacheGatewayAdapterSuite) TestSetStoresStringsVerbatim() {
+	s.port.On("Set", s.ctx, "k", "raw", time.Minute).Return(nil).Once()
+	s.Require().NoError(s.a.Set(s.ctx, "k", "raw", time.Minute))
+}
+
+func TestCacheGatewayAdapterSuite(t *testing.T) { suite.Run(t, new(CacheGatewayAdapterSuite)) }
@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuitBreaker.
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitStatus is a snapshot of a circuit breaker, as shown on the health endpoint.
+type CircuitStatus struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            *time.Time   `json:"openedAt,omitempty"`
+	RetryAt             *time.Time   `json:"retryAt,omitempty"`
+}
+
+// circuitBreaker stops calls to a failing upstream. It opens after threshold
+// consecutive failures; once cooldown has passed a single probe call is let
+// through (half open), whose outcome closes or re-opens it.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now, state: CircuitClosed}
+}
+
+// allow reports whether a call may be made now.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		return true
+	case CircuitHalfOpen:
+		// The probe is still in flight.
+		return false
+	default:
+		return true
+	}
+}
+
+// success records a call that reached a healthy upstream.
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state, b.failures = CircuitClosed, 0
+}
+
+// failure records a call that failed because of the upstream.
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt = CircuitOpen, b.now()
+	}
+}
+
+// release gives up a half-open probe whose outcome says nothing about the
+// upstream, such as a call cancelled by its caller.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.state = CircuitOpen
+		b.openedAt = b.now().Add(-b.cooldown)
+	}
+}
+
+func (b *circuitBreaker) status() CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := CircuitStatus{State: b.state, ConsecutiveFailures: b.failures}
+	if b.state != CircuitClosed {
+		opened, retry := b.openedAt, b.openedAt.Add(b.cooldown)
+		s.OpenedAt, s.RetryAt = &opened, &retry
+	}
+	return s
+}
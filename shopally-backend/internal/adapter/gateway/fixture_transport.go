@@ -0,0 +1,238 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/internal/config"
+)
+
+// FixtureMode selects what a FixtureTransport does with upstream calls.
+type FixtureMode string
+
+const (
+	// FixtureRecord calls upstream and saves every response as a fixture.
+	FixtureRecord FixtureMode = "record"
+	// FixtureReplay serves saved fixtures and never calls upstream.
+	FixtureReplay FixtureMode = "replay"
+
+	// DefaultFixtureDir is where fixtures are kept when no directory is configured.
+	DefaultFixtureDir = "fixtures"
+)
+
+// ErrFixtureNotFound is returned in replay mode for a request that was never recorded.
+var ErrFixtureNotFound = errors.New("no recorded fixture for request")
+
+// fixtureStrippedParams are query parameters left out of fixtures and of the
+// request fingerprint: credentials, signatures and per-call timestamps.
+var fixtureStrippedParams = []string{"sign", "timestamp", "app_key", "key", "apikey", "api_key", "access_key", "access_token"}
+
+// fixtureTimeRe matches RFC 3339 timestamps. They are masked when
+// fingerprinting request bodies, so prompts embedding fetch times replay.
+var fixtureTimeRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// FixtureTransport is an http.RoundTripper that records upstream responses
+// to fixture files, or replays them, for offline development, tests and
+// demos. Requests are matched by method, URL without the stripped parameters,
+// and body.
+type FixtureTransport struct {
+	mode FixtureMode
+	dir  string
+	next http.RoundTripper
+
+	// Redact lists secret values replaced wherever they appear in recorded
+	// fixtures, e.g. keys embedded in a URL path.
+	Redact []string
+
+	mu sync.Mutex // serializes fixture writes
+}
+
+// fixture is the file format of one recorded call.
+type fixture struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status      int    `json:"status"`
+		ContentType string `json:"contentType,omitempty"`
+		Body        string `json:"body"`
+	} `json:"response"`
+}
+
+// NewFixtureTransport creates a transport reading or writing fixtures in dir.
+// next carries recorded calls; if nil, http.DefaultTransport is used.
+func NewFixtureTransport(mode FixtureMode, dir string, next http.RoundTripper) (*FixtureTransport, error) {
+	switch mode {
+	case FixtureRecord:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create fixture dir: %w", err)
+		}
+	case FixtureReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("fixture dir: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown fixture mode %q", mode)
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &FixtureTransport{mode: mode, dir: dir, next: next}, nil
+}
+
+// NewFixtureTransportFromConfig returns the transport selected by
+// cfg.Fixtures, or nil when fixtures are disabled. The configured API keys
+// and secrets are redacted from recordings.
+func NewFixtureTransportFromConfig(cfg *config.Config) (*FixtureTransport, error) {
+	if cfg.Fixtures.Mode == "" {
+		return nil, nil
+	}
+	dir := cfg.Fixtures.Dir
+	if dir == "" {
+		dir = DefaultFixtureDir
+	}
+	t, err := NewFixtureTransport(FixtureMode(cfg.Fixtures.Mode), dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.Redact = []string{cfg.Aliexpress.AppKey, cfg.Aliexpress.AppSecret, cfg.Gemini.APIKey, os.Getenv("GEMINI_API_KEY"), cfg.FX.APIKEY}
+	log.Printf("[FixtureTransport] %s mode, fixtures in %s", t.mode, dir)
+	return t, nil
+}
+
+// Mode returns the transport's mode.
+func (t *FixtureTransport) Mode() FixtureMode { return t.mode }
+
+// Client returns an HTTP client with timeout sending through t, or nil, which
+// makes gateways use their default client, when t is nil.
+func (t *FixtureTransport) Client(timeout time.Duration) *http.Client {
+	if t == nil {
+		return nil
+	}
+	return &http.Client{Timeout: timeout, Transport: t}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	cleanURL := t.redact(strippedURL(req.URL))
+	path := filepath.Join(t.dir, fixtureName(req.Method, req.URL.Hostname(), cleanURL, body))
+
+	if t.mode == FixtureReplay {
+		return t.replay(req, path)
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	var f fixture
+	f.Request.Method, f.Request.URL, f.Request.Body = req.Method, cleanURL, t.redact(string(body))
+	f.Response.Status, f.Response.ContentType, f.Response.Body = resp.StatusCode, resp.Header.Get("Content-Type"), t.redact(string(respBody))
+	if err := t.save(path, f); err != nil {
+		log.Printf("[FixtureTransport] failed to record %s %s: %v", req.Method, cleanURL, err)
+	}
+	return resp, nil
+}
+
+func (t *FixtureTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s (%s)", ErrFixtureNotFound, req.Method, strippedURL(req.URL), filepath.Base(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("read fixture %s: %w", filepath.Base(path), err)
+	}
+	header := http.Header{}
+	if f.Response.ContentType != "" {
+		header.Set("Content-Type", f.Response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.Status, http.StatusText(f.Response.Status)),
+		StatusCode:    f.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(f.Response.Body)),
+		ContentLength: int64(len(f.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *FixtureTransport) save(path string, f fixture) error {
+	raw, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return os.WriteFile(path, raw, 0o644)
+}
+
+func (t *FixtureTransport) redact(s string) string {
+	for _, secret := range t.Redact {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "REDACTED")
+		}
+	}
+	return s
+}
+
+// strippedURL returns u without the stripped query parameters, with the
+// remaining ones in a stable order.
+func strippedURL(u *url.URL) string {
+	q := u.Query()
+	for _, p := range fixtureStrippedParams {
+		q.Del(p)
+	}
+	clean := *u
+	clean.RawQuery = q.Encode()
+	return clean.String()
+}
+
+// fixtureName is the file name of a request's fixture: the host for
+// browsing, then a fingerprint of the request.
+func fixtureName(method, host, cleanURL string, body []byte) string {
+	masked := fixtureTimeRe.ReplaceAll(body, []byte("<time>"))
+	sum := sha256.Sum256([]byte(method + "\n" + cleanURL + "\n" + string(masked)))
+	host = strings.NewReplacer(".", "_", ":", "_").Replace(host)
+	return host + "-" + hex.EncodeToString(sum[:10]) + ".json"
+}
@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// offline fails the test if a replaying transport reaches upstream.
+func offline(t *testing.T) http.RoundTripper {
+	return roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected upstream call in replay mode: %s", r.URL)
+		return nil, errors.New("network disabled")
+	})
+}
+
+func TestFixtureTransport_AlibabaRecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	var hits int32
+	upstream := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&hits, 1)
+		return jsonResponse(mockAliExpressResponseValid), nil
+	})
+
+	cfg := &config.Config{}
+	cfg.Aliexpress.BaseURL = "https://api-sg.aliexpress.test/sync"
+	cfg.Aliexpress.AppKey = "app-key-123"
+	cfg.Aliexpress.AppSecret = "app-secret-456"
+
+	rec, err := NewFixtureTransport(FixtureRecord, dir, upstream)
+	require.NoError(t, err)
+	rec.Redact = []string{cfg.Aliexpress.AppKey, cfg.Aliexpress.AppSecret}
+	recorded, err := NewAlibabaHTTPGateway(cfg, rec.Client(0)).FetchProducts(context.Background(), domain.SearchIntent{Keywords: "dress"}, 1, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, hits)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasPrefix(filepath.Base(files[0]), "api-sg_aliexpress_test-"))
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	for _, leaked := range []string{"app-key-123", "app-secret-456", "sign=", "app_key=", "timestamp="} {
+		assert.NotContains(t, string(raw), leaked)
+	}
+
+	// A fresh gateway signs with a new timestamp; the fixture still matches.
+	rep, err := NewFixtureTransport(FixtureReplay, dir, offline(t))
+	require.NoError(t, err)
+	replayed, err := NewAlibabaHTTPGateway(cfg, rep.Client(0)).FetchProducts(context.Background(), domain.SearchIntent{Keywords: "dress"}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+}
+
+func TestFixtureTransport_GeminiKeyRedactedAndReplayed(t *testing.T) {
+	dir := t.TempDir()
+	upstream := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return jsonResponse(`{"candidates":[{"content":{"parts":[{"text":"{\"keywords\":\"phone\",\"max_sale_price\":100,\"is_etb\":false}"}]}}]}`), nil
+	})
+
+	rec, err := NewFixtureTransport(FixtureRecord, dir, upstream)
+	require.NoError(t, err)
+	rec.Redact = []string{"gemini-secret"}
+	recorded, err := NewGeminiLLMGateway("gemini-secret", nil, rec.Client(0)).ParseIntent(context.Background(), "phone under 100 dollars")
+	require.NoError(t, err)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.Len(t, files, 1)
+	raw, _ := os.ReadFile(files[0])
+	assert.NotContains(t, string(raw), "gemini-secret")
+
+	// Replay does not need the real key.
+	rep, err := NewFixtureTransport(FixtureReplay, dir, offline(t))
+	require.NoError(t, err)
+	replayed, err := NewGeminiLLMGateway("replay", nil, rep.Client(0)).ParseIntent(context.Background(), "phone under 100 dollars")
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+}
+
+func TestFixtureTransport_ReplayMiss(t *testing.T) {
+	rep, err := NewFixtureTransport(FixtureReplay, t.TempDir(), offline(t))
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.test/rates?from=USD&key=secret", nil)
+	_, err = rep.RoundTrip(req)
+	assert.ErrorIs(t, err, ErrFixtureNotFound)
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestFixtureTransport_Config(t *testing.T) {
+	cfg := &config.Config{}
+	tr, err := NewFixtureTransportFromConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, tr)
+	assert.Nil(t, tr.Client(0))
+
+	cfg.Fixtures.Mode = "replay"
+	cfg.Fixtures.Dir = filepath.Join(t.TempDir(), "missing")
+	_, err = NewFixtureTransportFromConfig(cfg)
+	assert.Error(t, err)
+
+	cfg.Fixtures.Mode = "bogus"
+	_, err = NewFixtureTransportFromConfig(cfg)
+	assert.Error(t, err)
+}
+
+func TestFixtureName_MasksBodyTimestamps(t *testing.T) {
+	a := fixtureName("POST", "h", "https://h/x", []byte(`{"at":"2026-01-02T03:04:05Z"}`))
+	b := fixtureName("POST", "h", "https://h/x", []byte(`{"at":"2026-05-06T07:08:09.123+03:00"}`))
+	c := fixtureName("POST", "h", "https://h/x", []byte(`{"at":"other"}`))
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
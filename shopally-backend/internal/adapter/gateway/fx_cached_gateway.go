@@ -7,13 +7,18 @@ import (
 	"time"
 
 	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
 )
 
+// CachedFXClient caches rates from Inner. Concurrent misses for the same pair
+// share one provider call and write-through.
 type CachedFXClient struct {
 	Inner  domain.IFXClient
 	Cache  domain.ICachePort
 	TTL    time.Duration
 	Prefix string // optional key prefix, e.g., "fx:"
+
+	fetches util.CallGroup[domain.FXQuote]
 }
 
 func NewCachedFXClient(inner domain.IFXClient, cache domain.ICachePort, ttl time.Duration) *CachedFXClient {
@@ -51,22 +56,69 @@ func (c *CachedFXClient) GetRate(ctx context.Context, from, to string) (float64,
 		}
 	}
 
-	// 2) Cache miss -> fetch from provider
-	rate, err := c.Inner.GetRate(ctx, f, t)
-	if err != nil {
-		return 0, err
-	}
+	// 2) Cache miss -> fetch from provider and write through, once for all
+	// concurrent callers
+	q, err := c.fetch(ctx, key, f, t)
+	return q.Rate, err
+}
+
+// GetQuote is like GetRate but also returns when the rate was fetched from the
+// provider. The fetch time is cached next to the rate under "<key>:ts"; a rate
+// without a timestamp is treated as a miss.
+func (c *CachedFXClient) GetQuote(ctx context.Context, from, to string) (domain.FXQuote, error) {
+	f := strings.ToUpper(strings.TrimSpace(from))
+	t := strings.ToUpper(strings.TrimSpace(to))
+	key := c.key(f, t)
+	tsKey := key + ":ts"
 
-	// 3) Write-through
 	if c.Cache != nil {
-		_ = c.Cache.Set(ctx, key, formatFloat(rate), c.TTL)
+		val, ok, err := c.Cache.Get(ctx, key)
+		if err == nil && ok {
+			rate, perr := strconv.ParseFloat(val, 64)
+			ts, tok, terr := c.Cache.Get(ctx, tsKey)
+			if perr == nil && terr == nil && tok {
+				if fetchedAt, err := time.Parse(time.RFC3339, ts); err == nil {
+					return domain.FXQuote{Rate: rate, FetchedAt: fetchedAt}, nil
+				}
+			}
+		}
 	}
 
-	return rate, nil
+	return c.fetch(ctx, key, f, t)
+}
+
+// fetch gets a quote from Inner and caches the rate and its fetch time, so
+// rates cached by GetRate also serve GetQuote.
+func (c *CachedFXClient) fetch(ctx context.Context, key, from, to string) (domain.FXQuote, error) {
+	q, _, err := c.fetches.Do(ctx, key, func(ctx context.Context) (domain.FXQuote, error) {
+		var q domain.FXQuote
+		if inner, ok := c.Inner.(domain.IFXQuoteClient); ok {
+			var err error
+			if q, err = inner.GetQuote(ctx, from, to); err != nil {
+				return domain.FXQuote{}, err
+			}
+		} else {
+			rate, err := c.Inner.GetRate(ctx, from, to)
+			if err != nil {
+				return domain.FXQuote{}, err
+			}
+			q = domain.FXQuote{Rate: rate, FetchedAt: time.Now().UTC()}
+		}
+
+		if c.Cache != nil {
+			_ = c.Cache.Set(ctx, key, formatFloat(q.Rate), c.TTL)
+			_ = c.Cache.Set(ctx, key+":ts", q.FetchedAt.UTC().Format(time.RFC3339), c.TTL)
+		}
+		return q, nil
+	})
+	return q, err
 }
 
 func formatFloat(f float64) string {
 	return strconv.FormatFloat(f, 'f', 6, 64)
 }
 
-var _ domain.IFXClient = (*CachedFXClient)(nil)
+var (
+	_ domain.IFXClient      = (*CachedFXClient)(nil)
+	_ domain.IFXQuoteClient = (*CachedFXClient)(nil)
+)
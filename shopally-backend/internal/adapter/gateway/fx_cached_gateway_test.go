@@ -3,11 +3,15 @@ package gateway
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -29,8 +33,9 @@ func (s *CachedFXClientSuite) SetupTest() {
 func (s *CachedFXClientSuite) TestMissThenHit() {
 	key := "fx:USD:ETB"
 	s.cache.On("Get", s.ctx, key).Return("", false, nil).Once()
-	s.fx.On("GetRate", s.ctx, "USD", "ETB").Return(56.123456, nil).Once()
-	s.cache.On("Set", s.ctx, key, "56.123456", time.Minute).Return(nil).Once()
+	s.fx.On("GetRate", mock.Anything, "USD", "ETB").Return(56.123456, nil).Once()
+	s.cache.On("Set", mock.Anything, key, "56.123456", time.Minute).Return(nil).Once()
+	s.cache.On("Set", mock.Anything, key+":ts", mock.AnythingOfType("string"), time.Minute).Return(nil).Once()
 
 	rate1, err1 := s.c.GetRate(s.ctx, "usd", "etb")
 	s.Require().NoError(err1)
@@ -46,8 +51,9 @@ func (s *CachedFXClientSuite) TestCacheHitParseErrorFallsThrough() {
 	key := "fx:USD:ETB"
 	// bad cached value -> fall through to provider
 	s.cache.On("Get", s.ctx, key).Return("not-a-number", true, nil).Once()
-	s.fx.On("GetRate", s.ctx, "USD", "ETB").Return(57.5, nil).Once()
-	s.cache.On("Set", s.ctx, key, "57.500000", time.Minute).Return(nil).Once()
+	s.fx.On("GetRate", mock.Anything, "USD", "ETB").Return(57.5, nil).Once()
+	s.cache.On("Set", mock.Anything, key, "57.500000", time.Minute).Return(nil).Once()
+	s.cache.On("Set", mock.Anything, key+":ts", mock.AnythingOfType("string"), time.Minute).Return(nil).Once()
 
 	rate, err := s.c.GetRate(s.ctx, "USD", "ETB")
 	s.Require().NoError(err)
@@ -58,8 +64,9 @@ func (s *CachedFXClientSuite) TestCacheErrorFallsThrough() {
 	key := "fx:USD:ETB"
 	// cache error -> treat as miss and continue
 	s.cache.On("Get", s.ctx, key).Return("", false, errors.New("boom")).Once()
-	s.fx.On("GetRate", s.ctx, "USD", "ETB").Return(60.25, nil).Once()
-	s.cache.On("Set", s.ctx, key, "60.250000", time.Minute).Return(nil).Once()
+	s.fx.On("GetRate", mock.Anything, "USD", "ETB").Return(60.25, nil).Once()
+	s.cache.On("Set", mock.Anything, key, "60.250000", time.Minute).Return(nil).Once()
+	s.cache.On("Set", mock.Anything, key+":ts", mock.AnythingOfType("string"), time.Minute).Return(nil).Once()
 
 	rate, err := s.c.GetRate(s.ctx, "USD", "ETB")
 	s.Require().NoError(err)
@@ -69,7 +76,7 @@ func (s *CachedFXClientSuite) TestCacheErrorFallsThrough() {
 func (s *CachedFXClientSuite) TestProviderError() {
 	key := "fx:USD:ETB"
 	s.cache.On("Get", s.ctx, key).Return("", false, nil).Once()
-	s.fx.On("GetRate", s.ctx, "USD", "ETB").Return(0.0, errors.New("provider down")).Once()
+	s.fx.On("GetRate", mock.Anything, "USD", "ETB").Return(0.0, errors.New("provider down")).Once()
 
 	rate, err := s.c.GetRate(s.ctx, "USD", "ETB")
 	s.Error(err)
@@ -82,3 +89,114 @@ func TestCachedFXClientSuite(t *testing.T) { suite.Run(t, new(CachedFXClientSuit
 func TestFormatFloat(t *testing.T) {
 	assert.Equal(t, "1.230000", formatFloat(1.23))
 }
+
+func (s *CachedFXClientSuite) TestGetQuote_HitUsesCachedTimestamp() {
+	s.cache.On("Get", s.ctx, "fx:USD:ETB").Return("56.500000", true, nil).Once()
+	s.cache.On("Get", s.ctx, "fx:USD:ETB:ts").Return("2025-08-22T10:00:00Z", true, nil).Once()
+
+	q, err := s.c.GetQuote(s.ctx, "USD", "ETB")
+	s.Require().NoError(err)
+	s.InDelta(56.5, q.Rate, 1e-9)
+	s.Equal(time.Date(2025, 8, 22, 10, 0, 0, 0, time.UTC), q.FetchedAt)
+}
+
+func (s *CachedFXClientSuite) TestGetQuote_ServesRateCachedByGetRate() {
+	cache := newMemPort()
+	c := NewCachedFXClient(s.fx, cache, time.Minute)
+	s.fx.On("GetRate", mock.Anything, "USD", "ETB").Return(57.0, nil).Once()
+
+	_, err := c.GetRate(s.ctx, "USD", "ETB")
+	s.Require().NoError(err)
+	q, err := c.GetQuote(s.ctx, "USD", "ETB")
+	s.Require().NoError(err)
+	s.InDelta(57.0, q.Rate, 1e-9)
+	s.False(q.FetchedAt.IsZero())
+}
+
+func (s *CachedFXClientSuite) TestGetQuote_MissingTimestampRefetches() {
+	s.cache.On("Get", s.ctx, "fx:USD:ETB").Return("56.500000", true, nil).Once()
+	s.cache.On("Get", s.ctx, "fx:USD:ETB:ts").Return("", false, nil).Once()
+	s.fx.On("GetRate", mock.Anything, "USD", "ETB").Return(57.0, nil).Once()
+	s.cache.On("Set", mock.Anything, "fx:USD:ETB", "57.000000", time.Minute).Return(nil).Once()
+	s.cache.On("Set", mock.Anything, "fx:USD:ETB:ts", mock.AnythingOfType("string"), time.Minute).Return(nil).Once()
+
+	q, err := s.c.GetQuote(s.ctx, "USD", "ETB")
+	s.Require().NoError(err)
+	s.InDelta(57.0, q.Rate, 1e-9)
+	s.False(q.FetchedAt.IsZero())
+}
+
+// memPort is an in-memory ICachePort.
+type memPort struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemPort() *memPort { return &memPort{data: map[string]string{}} }
+
+func (m *memPort) Get(ctx context.Context, key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	return v, ok, nil
+}
+
+func (m *memPort) Set(ctx context.Context, key, val string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = val
+	return nil
+}
+
+// slowQuoteClient returns a fixed quote once release is closed.
+type slowQuoteClient struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (c *slowQuoteClient) GetRate(ctx context.Context, from, to string) (float64, error) {
+	q, err := c.GetQuote(ctx, from, to)
+	return q.Rate, err
+}
+
+func (c *slowQuoteClient) GetQuote(ctx context.Context, from, to string) (domain.FXQuote, error) {
+	atomic.AddInt32(&c.calls, 1)
+	select {
+	case <-c.release:
+		return domain.FXQuote{Rate: 155.5, FetchedAt: time.Now().UTC()}, nil
+	case <-ctx.Done():
+		return domain.FXQuote{}, ctx.Err()
+	}
+}
+
+func TestCachedFXClient_CoalescesConcurrentMisses(t *testing.T) {
+	inner := &slowQuoteClient{release: make(chan struct{})}
+	c := NewCachedFXClient(inner, nil, time.Minute)
+
+	const callers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q, err := c.GetQuote(context.Background(), "USD", "ETB")
+			assert.NoError(t, err)
+			assert.InDelta(t, 155.5, q.Rate, 1e-9)
+		}()
+	}
+
+	for atomic.LoadInt32(&inner.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// One caller giving up leaves the shared fetch running for the others.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.GetQuote(ctx, "USD", "ETB")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	time.Sleep(50 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&inner.calls))
+}
@@ -23,7 +23,10 @@ type FXHTTPGateway struct {
 	HTTPClient *http.Client
 }
 
-var _ domain.IFXClient = (*FXHTTPGateway)(nil)
+var (
+	_ domain.IFXClient      = (*FXHTTPGateway)(nil)
+	_ domain.IFXQuoteClient = (*FXHTTPGateway)(nil)
+)
 
 // NewFXHTTPGateway creates a new gateway. If httpClient is nil, a default client is used.
 func NewFXHTTPGateway(apiURL, apiKey string, httpClient *http.Client) *FXHTTPGateway {
@@ -106,6 +109,15 @@ func (g *FXHTTPGateway) GetRate(ctx context.Context, from, to string) (float64,
 	return 0, fmt.Errorf("unrecognized fx response for %s", reqURL)
 }
 
+// GetQuote fetches the rate and stamps it with the fetch time.
+func (g *FXHTTPGateway) GetQuote(ctx context.Context, from, to string) (domain.FXQuote, error) {
+	rate, err := g.GetRate(ctx, from, to)
+	if err != nil {
+		return domain.FXQuote{}, err
+	}
+	return domain.FXQuote{Rate: rate, FetchedAt: time.Now().UTC()}, nil
+}
+
 func (g *FXHTTPGateway) buildRequestURL(from, to string) string {
 	apiURL := g.APIURL
 	if apiURL == "" {
@@ -72,15 +72,19 @@ func (g *GeminiLLMGateway) CompareProducts(ctx context.Context, productDetails [
 }
 
 // NewGeminiLLMGateway creates a new gateway using the GEMINI_API_KEY from env if apiKey is empty.
-func NewGeminiLLMGateway(apiKey string, fx domain.IFXClient) domain.LLMGateway {
+// If httpClient is nil, a default client is used.
+func NewGeminiLLMGateway(apiKey string, fx domain.IFXClient, httpClient *http.Client) domain.LLMGateway {
 	if apiKey == "" {
 		apiKey = os.Getenv("GEMINI_API_KEY")
 	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 12 * time.Second}
+	}
 
 	return &GeminiLLMGateway{
 		apiKey:   apiKey,
 		modelURL: "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent",
-		client:   &http.Client{Timeout: 12 * time.Second},
+		client:   httpClient,
 		fx:       fx,
 	}
 }
@@ -162,9 +166,7 @@ func (g *GeminiLLMGateway) call(ctx context.Context, prompt string) (string, err
 }
 
 // ParseIntent asks the model to extract a structured JSON of constraints.
-// ParseIntent asks the model to extract a structured JSON of constraints.
-// ParseIntent asks the model to extract a structured JSON of constraints.
-func (g *GeminiLLMGateway) ParseIntent(ctx context.Context, query string) (map[string]interface{}, error) {
+func (g *GeminiLLMGateway) ParseIntent(ctx context.Context, query string) (*domain.SearchIntent, error) {
 	requestID := ""
 	if requestID == "" {
 		requestID = "unknown"
@@ -172,11 +174,7 @@ func (g *GeminiLLMGateway) ParseIntent(ctx context.Context, query string) (map[s
 
 	normalizedQuery := strings.TrimSpace(query)
 
-	// 2) Content moderation: Check for potentially harmful content
-	if isPotentiallyHarmful(normalizedQuery) {
-		log.Printf("[%s] Blocked query due to potentially harmful content: %s", requestID, normalizedQuery)
-		return nil, errors.New("query contains potentially harmful or prohibited content")
-	}
+	// Content moderation runs before the pipeline, see usecase.ContentPolicy.
 
 	// 3) Build a STRICT JSON-only prompt for intent parsing that handles both English and Amharic
 	prompt := fmt.Sprintf(`STRICT INSTRUCTIONS: OUTPUT ONLY RAW JSON, NO OTHER TEXT, NO EXPLANATIONS, NO CODE BLOCKS.
@@ -188,7 +186,7 @@ RULES:
 - Understand queries in English, Amharic (ፊደል or latin script), or mixed languages
 - Extract and translate all content to English for the JSON output
 - Use null for missing parameters
-- All prices should be converted to and output in USD
+- Output prices exactly as stated by the user, in the user's currency (see is_etb); never convert currencies
 - Detect prices written in words or numbers (e.g., "five hundred" = 500, "አምስት መቶ" = 500, "ሁለት ሺህ" = 2000)
 - Understand price ranges: "under 1000", "over 500", "between 100 and 200", "around 1500", "ከ500 በታች", "ከ1000 በላይ"
 - Understand price-related terms in any language: "cheap"/"ርካሽ", "expensive"/"ውድ", "affordable", "budget"/"በጀት", "pricey"
@@ -202,7 +200,7 @@ CURRENCY HANDLING:
 - If user specifies "ETB", "birr", "ብር" → is_etb = true
 - If user specifies "$", "USD", "dollars" → is_etb = false  
 - If no currency specified → is_etb = true (default to ETB)
-- Always convert and output prices in USD regardless of is_etb value
+- Do NOT convert amounts: output ETB amounts when is_etb = true and USD amounts when is_etb = false
 
 LANGUAGE HANDLING:
 - Extract keywords in English regardless of input language
@@ -212,9 +210,10 @@ LANGUAGE HANDLING:
 JSON SCHEMA:
 {
   "keywords": "string",           // Always in English, extracted from any language input
-  "category_ids": "string|null",
-  "min_sale_price": number|null,  // Always in USD
-  "max_sale_price": number|null,  // Always in USD
+  "category": "string|null",      // Product category as the user wrote it, in any language (e.g. "phones", "ጫማ")
+  "category_ids": "string|null",  // Only numeric AliExpress category IDs the user typed; never guess
+  "min_sale_price": number|null,  // In ETB if is_etb, otherwise USD
+  "max_sale_price": number|null,  // In ETB if is_etb, otherwise USD
   "delivery_days": number|null,
   "ship_to_country": "ET",
   "target_currency": "USD",
@@ -224,19 +223,19 @@ JSON SCHEMA:
 
 EXAMPLES (User Query in any language -> English JSON Output):
 
-"ስልክ ከአምስት ሺህ ብር በታች" -> {"keywords":"phone","min_sale_price":null,"max_sale_price":85.00,"category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
+"ስልክ ከአምስት ሺህ ብር በታች" -> {"keywords":"phone","min_sale_price":null,"max_sale_price":5000.0,"category":"ስልክ","category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
 
-"gaming laptop under one thousand five hundred dollars" -> {"keywords":"gaming laptop","min_sale_price":null,"max_sale_price":1500.0,"category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":false}
+"gaming laptop under one thousand five hundred dollars" -> {"keywords":"gaming laptop","min_sale_price":null,"max_sale_price":1500.0,"category":"laptop","category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":false}
 
-"የቤት እቃዎች ከ100 እስከ 200 ዶላር" -> {"keywords":"home appliances","min_sale_price":100.0,"max_sale_price":200.0,"category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":false}
+"የቤት እቃዎች ከ100 እስከ 200 ዶላር" -> {"keywords":"home appliances","min_sale_price":100.0,"max_sale_price":200.0,"category":"የቤት እቃዎች","category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":false}
 
-"ርካሽ ሻጭ" -> {"keywords":"shoes","min_sale_price":null,"max_sale_price":20.00,"category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
+"ርካሽ ሻጭ" -> {"keywords":"shoes","min_sale_price":null,"max_sale_price":null,"category":null,"category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
 
-"expensive electronics over two thousand" -> {"keywords":"electronics","min_sale_price":34.00,"max_sale_price":null,"category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
+"expensive electronics over two thousand" -> {"keywords":"electronics","min_sale_price":2000.0,"max_sale_price":null,"category":"electronics","category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
 
-"በጀት ኮምፒዩተር ከአስር ሺህ ብር በታች" -> {"keywords":"computer","min_sale_price":null,"max_sale_price":170.00,"category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
+"በጀት ኮምፒዩተር ከአስር ሺህ ብር በታች" -> {"keywords":"computer","min_sale_price":null,"max_sale_price":10000.0,"category":"ኮምፒዩተር","category_ids":null,"delivery_days":null,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
 
-"ውድ ሰዓት በ5 ቀናት ውስጥ" -> {"keywords":"watch","min_sale_price":50.0,"max_sale_price":null,"category_ids":null,"delivery_days":5,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
+"ውድ ሰዓት በ5 ቀናት ውስጥ" -> {"keywords":"watch","min_sale_price":null,"max_sale_price":null,"category":"ሰዓት","category_ids":null,"delivery_days":5,"ship_to_country":"ET","target_currency":"USD","target_language":"en","is_etb":true}
 
 INPUT QUERY: "%s"
 OUTPUT:`, normalizedQuery)
@@ -252,43 +251,84 @@ OUTPUT:`, normalizedQuery)
 	clean := extractStrictJSON(text)
 	log.Printf("[%s] Extracted JSON: %s", requestID, clean)
 
-	// Parse the JSON response directly into map
-	var m map[string]interface{}
-	if err := json.Unmarshal([]byte(clean), &m); err != nil {
+	// Parse the JSON response into the typed intent
+	var parsed llmIntent
+	if err := json.Unmarshal([]byte(clean), &parsed); err != nil {
 		log.Printf("[%s] Failed to parse LLM JSON response: %v. Raw: %s", requestID, err, clean)
-		// Fallback to minimal response with default is_etb = true
-		m = map[string]interface{}{
-			"keywords":        normalizedQuery,
-			"category_ids":    nil,
-			"min_sale_price":  nil,
-			"max_sale_price":  nil,
-			"delivery_days":   nil,
-			"ship_to_country": "ET",
-			"target_currency": "USD",
-			"target_language": "en",
-			"is_etb":          true, // Default to ETB
-		}
+		// Fallback to the raw query; prices default to ETB
+		parsed = llmIntent{}
 	}
+	intent := parsed.toSearchIntent()
 
-	// Enforce required fields
-	m["ship_to_country"] = "ET"
-	m["target_currency"] = "USD"
-	m["target_language"] = "en"
+	// Shipping is always to Ethiopia
+	intent.ShipToCountry = domain.DefaultShipToCountry
 
-	// Ensure is_etb field exists and is boolean, default to true if missing
-	if _, exists := m["is_etb"]; !exists {
-		m["is_etb"] = true
+	// Ensure keywords exist (basic fallback)
+	if intent.Keywords == "" {
+		// If LLM failed to extract keywords, use original query but this should be rare
+		intent.Keywords = normalizedQuery
 	}
 
-	// Ensure keywords exist and are in English (basic fallback)
-	if keywords, ok := m["keywords"].(string); !ok || strings.TrimSpace(keywords) == "" {
-		// If LLM failed to extract keywords, use original query but this should be rare
-		m["keywords"] = normalizedQuery
-	} else {
-		m["keywords"] = strings.TrimSpace(keywords)
+	return intent, nil
+}
+
+// RefineIntent asks the model how a follow-up query changes the previous
+// intent and returns only the changes.
+func (g *GeminiLLMGateway) RefineIntent(ctx context.Context, previous domain.SearchIntent, seenIDs []string, followUp string) (*domain.IntentDelta, error) {
+	prevJSON, err := json.Marshal(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := fmt.Sprintf(`STRICT INSTRUCTIONS: OUTPUT ONLY RAW JSON, NO OTHER TEXT, NO EXPLANATIONS, NO CODE BLOCKS.
+
+You refine an e-commerce search. The user already searched with the PREVIOUS INTENT below and has seen %d results. Their FOLLOW-UP may be in English, Amharic or mixed. Output ONLY the fields the follow-up changes.
+
+RULES:
+- Output pure JSON only, in English
+- Omit or use null for fields the follow-up does not change
+- "keywords": the full new English keywords when the product or its attributes change (e.g. previous "phone" + "only red" -> "red phone")
+- Prices: output exactly as stated, never convert; is_etb as in the original parser (true for ETB/birr/ብር or no currency, false for USD/$)
+- "cheaper ones"/"ርካሽ" without an amount -> "sort":"cheapest"; with the previous max price P, also set max_sale_price below P
+- "faster"/"ፈጣን" -> "sort":"fastest"; "best reviewed" -> "sort":"best_rated"; "popular" -> "sort":"most_sold"
+- "category": a newly named product category as the user wrote it (e.g. "ጫማ", "headphones"); never guess category_ids
+- "clear": list of fields the user removes, from ["minPrice","maxPrice","categoryId","maxDeliveryDays","sort"] (e.g. "any price" -> ["minPrice","maxPrice"])
+
+JSON SCHEMA:
+{
+  "keywords": "string|null",
+  "min_sale_price": number|null,
+  "max_sale_price": number|null,
+  "is_etb": boolean|null,
+  "category": "string|null",
+  "category_ids": "string|null",
+  "delivery_days": number|null,
+  "sort": "cheapest|fastest|best_rated|most_sold|null",
+  "clear": ["string"]
+}
+
+EXAMPLES:
+PREVIOUS {"keywords":"phone","maxPrice":5000,"currency":"ETB"} + "only red" -> {"keywords":"red phone","clear":[]}
+PREVIOUS {"keywords":"shoes"} + "ከ2000 ብር በታች" -> {"max_sale_price":2000,"is_etb":true,"clear":[]}
+PREVIOUS {"keywords":"watch","maxPrice":50,"currency":"USD"} + "any price, but fast delivery" -> {"sort":"fastest","clear":["minPrice","maxPrice"]}
+
+PREVIOUS INTENT: %s
+FOLLOW-UP: "%s"
+OUTPUT:`, len(seenIDs), prevJSON, strings.TrimSpace(followUp))
+
+	text, err := g.call(ctx, prompt)
+	if err != nil {
+		return nil, err
 	}
+	clean := extractStrictJSON(text)
+	log.Printf("Extracted intent delta JSON: %s", clean)
 
-	return m, nil
+	var parsed llmIntentDelta
+	if err := json.Unmarshal([]byte(clean), &parsed); err != nil {
+		log.Printf("Failed to parse intent delta JSON: %v", err)
+		return nil, err
+	}
+	return parsed.toIntentDelta(), nil
 }
 
 // extractStrictJSON aggressively extracts JSON from LLM response
@@ -419,25 +459,6 @@ func extractJSON(s string) string {
 
 // func fmtInt(i int) string { return fmt.Sprintf("%d", i) }
 
-// isPotentiallyHarmful checks a query for keywords associated with illegal or adult content.
-// This is a basic implementation and should be expanded significantly for production use.
-func isPotentiallyHarmful(query string) bool {
-	lowerQuery := strings.ToLower(query)
-	blacklist := []string{
-		"drugs", "weapons", "firearms", "explosives", "contraband",
-		"porn", "sex toys", "adult content", "erotic", "hentai",
-		"illegal", "smuggled", "stolen goods", "counterfeit",
-		"hate speech", "violence", "racist", "discriminatory",
-	}
-
-	for _, keyword := range blacklist {
-		if strings.Contains(lowerQuery, keyword) {
-			return true
-		}
-	}
-	return false
-}
-
 // Heuristic Amharic detection: Unicode Ethiopic block or common tokens
 func (g *GeminiLLMGateway) SummarizeProduct(ctx context.Context, p *domain.Product, userPrompt string) (*domain.Product, error) {
 	lang, _ := ctx.Value(contextkeys.RespLang).(string)
@@ -510,20 +531,29 @@ OUTPUT:`, userPrompt, lang, lang, getProductJSONString(p), strings.ToUpper(lang)
 		return nil, err
 	}
 
-	// Ensure critical fields remain unchanged
-	enhancedProduct.ID = p.ID
-	enhancedProduct.ImageURL = p.ImageURL
-	enhancedProduct.AIMatchPercentage = aiMatchPercentage
-	enhancedProduct.Price = p.Price
-	enhancedProduct.ProductRating = p.ProductRating
-	enhancedProduct.SellerScore = p.SellerScore
-	enhancedProduct.DeliveryEstimate = p.DeliveryEstimate
-	enhancedProduct.NumberSold = p.NumberSold
-	enhancedProduct.DeeplinkURL = p.DeeplinkURL
-	enhancedProduct.TaxRate = p.TaxRate
-	enhancedProduct.Discount = p.Discount
-
-	return &enhancedProduct, nil
+	// Start from the original so every non-text field remains unchanged, then
+	// take only the enhanced text fields from the model output.
+	out := *p
+	out.AIMatchPercentage = aiMatchPercentage
+	out.AIEnriched = true
+	if t := strings.TrimSpace(enhancedProduct.Title); t != "" {
+		out.Title = t
+	}
+	out.Description = enhancedProduct.Description
+	out.CustomerHighlights = enhancedProduct.CustomerHighlights
+	out.CustomerReview = enhancedProduct.CustomerReview
+	out.SummaryBullets = enhancedProduct.SummaryBullets
+
+	return &out, nil
+}
+
+// FallbackSummary implements domain.LLMGateway using the heuristic content only.
+func (g *GeminiLLMGateway) FallbackSummary(ctx context.Context, p *domain.Product, userPrompt string) *domain.Product {
+	lang, _ := ctx.Value(contextkeys.RespLang).(string)
+	if lang == "" {
+		lang = "en"
+	}
+	return g.createBasicEnhancedProduct(p, userPrompt, lang, calculateAIMatchPercentage(p, userPrompt))
 }
 
 // getProductJSONString returns the product as a JSON string for the prompt
@@ -553,25 +583,14 @@ func getProductJSONString(p *domain.Product) string {
 
 // createBasicEnhancedProduct creates enhanced content without LLM
 func (g *GeminiLLMGateway) createBasicEnhancedProduct(p *domain.Product, userPrompt, lang string, aiMatchPercentage int) *domain.Product {
-	enhanced := &domain.Product{
-		ID:                 p.ID,
-		Title:              p.Title,
-		ImageURL:           p.ImageURL,
-		AIMatchPercentage:  aiMatchPercentage,
-		Price:              p.Price,
-		ProductRating:      p.ProductRating,
-		SellerScore:        p.SellerScore,
-		DeliveryEstimate:   p.DeliveryEstimate,
-		Description:        enhanceDescription(p.Description, lang),
-		CustomerHighlights: enhanceHighlights(p.CustomerHighlights, lang),
-		CustomerReview:     enhanceReview(p.CustomerReview, lang),
-		NumberSold:         p.NumberSold,
-		SummaryBullets:     createSummaryBullets(p, lang),
-		DeeplinkURL:        p.DeeplinkURL,
-		TaxRate:            p.TaxRate,
-		Discount:           p.Discount,
-	}
-	return enhanced
+	enhanced := *p
+	enhanced.AIMatchPercentage = aiMatchPercentage
+	enhanced.AIEnriched = false
+	enhanced.Description = enhanceDescription(p.Description, lang)
+	enhanced.CustomerHighlights = enhanceHighlights(p.CustomerHighlights, lang)
+	enhanced.CustomerReview = enhanceReview(p.CustomerReview, lang)
+	enhanced.SummaryBullets = createSummaryBullets(p, lang)
+	return &enhanced
 }
 
 func enhanceDescription(desc, lang string) string {
@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// llmIntent is the JSON object the intent prompt asks the model for. Values
+// are decoded leniently because models sometimes quote numbers or emit a
+// number where a string is expected.
+type llmIntent struct {
+	Keywords      flexString `json:"keywords"`
+	MinSalePrice  flexNumber `json:"min_sale_price"`
+	MaxSalePrice  flexNumber `json:"max_sale_price"`
+	Category      flexString `json:"category"`
+	CategoryIDs   flexString `json:"category_ids"`
+	DeliveryDays  flexNumber `json:"delivery_days"`
+	ShipToCountry flexString `json:"ship_to_country"`
+	IsETB         *bool      `json:"is_etb"`
+}
+
+// toSearchIntent converts the model output. Prices are in ETB unless the
+// model reported is_etb=false.
+func (l llmIntent) toSearchIntent() *domain.SearchIntent {
+	intent := &domain.SearchIntent{
+		Keywords:      strings.TrimSpace(string(l.Keywords)),
+		MinPrice:      l.MinSalePrice.v,
+		MaxPrice:      l.MaxSalePrice.v,
+		CategoryID:    string(l.CategoryIDs),
+		CategoryName:  string(l.Category),
+		ShipToCountry: string(l.ShipToCountry),
+		Currency:      "ETB",
+	}
+	if l.IsETB != nil && !*l.IsETB {
+		intent.Currency = "USD"
+	}
+	if d := l.DeliveryDays.v; d != nil {
+		days := int(math.Ceil(*d))
+		intent.MaxDeliveryDays = &days
+	}
+	return intent
+}
+
+// flexNumber decodes a JSON number or numeric string; anything else,
+// including null, leaves it unset.
+type flexNumber struct{ v *float64 }
+
+func (n *flexNumber) UnmarshalJSON(b []byte) error {
+	n.v = nil
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil
+		}
+		b = []byte(strings.ReplaceAll(strings.TrimSpace(s), ",", ""))
+	}
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	n.v = &f
+	return nil
+}
+
+// flexString decodes a JSON string or number; anything else leaves it empty.
+type flexString string
+
+func (s *flexString) UnmarshalJSON(b []byte) error {
+	*s = ""
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		*s = flexString(strings.TrimSpace(str))
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(b, &num); err == nil {
+		*s = flexString(num.String())
+	}
+	return nil
+}
+
+// llmIntentDelta is the JSON the refinement prompt asks the model for.
+type llmIntentDelta struct {
+	llmIntent
+	Sort  flexString `json:"sort"`
+	Clear []string   `json:"clear"`
+}
+
+// toIntentDelta converts the model output. Unlike toSearchIntent it sets
+// nothing the model left out, so the previous intent keeps those values.
+func (l llmIntentDelta) toIntentDelta() *domain.IntentDelta {
+	set := domain.SearchIntent{
+		Keywords:     strings.TrimSpace(string(l.Keywords)),
+		MinPrice:     l.MinSalePrice.v,
+		MaxPrice:     l.MaxSalePrice.v,
+		CategoryID:   string(l.CategoryIDs),
+		CategoryName: string(l.Category),
+		Sort:         string(l.Sort),
+	}
+	if set.MinPrice != nil || set.MaxPrice != nil {
+		set.Currency = "ETB"
+		if l.IsETB != nil && !*l.IsETB {
+			set.Currency = "USD"
+		}
+	}
+	if d := l.DeliveryDays.v; d != nil {
+		days := int(math.Ceil(*d))
+		set.MaxDeliveryDays = &days
+	}
+	return &domain.IntentDelta{Set: set, Clear: l.Clear}
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/shopally-ai/pkg/domain"
@@ -14,7 +15,7 @@ func NewMockAlibabaGateway() domain.AlibabaGateway {
 	return &MockAlibabaGateway{}
 }
 
-func (m *MockAlibabaGateway) FetchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*domain.Product, error) {
+func (m *MockAlibabaGateway) FetchProducts(ctx context.Context, intent domain.SearchIntent, pageNo, pageSize int) (*domain.ProductPage, error) {
 	fxTs, _ := time.Parse(time.RFC3339, "2025-08-22T10:00:00Z")
 
 	products := []*domain.Product{
@@ -95,5 +96,52 @@ func (m *MockAlibabaGateway) FetchProducts(ctx context.Context, query string, fi
 		},
 	}
 
-	return products, nil
+	return &domain.ProductPage{
+		Products:         products,
+		TotalRecordCount: len(products),
+		CurrentPageNo:    1,
+		PageSize:         len(products),
+	}, nil
+}
+
+// FetchProductDetail returns the mock product with the given ID.
+func (m *MockAlibabaGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	page, _ := m.FetchProducts(ctx, domain.SearchIntent{}, 1, 0)
+	for _, p := range page.Products {
+		if p.ID == productID {
+			p.Images = []string{p.ImageURL}
+			p.Shop = &domain.Shop{ID: "MOCK-SHOP", Name: "Mock Shop"}
+			return p, nil
+		}
+	}
+	return nil, domain.ErrProductNotFound
+}
+
+// FetchCategories returns a small fixed category tree.
+func (m *MockAlibabaGateway) FetchCategories(ctx context.Context) ([]domain.Category, error) {
+	return []domain.Category{
+		{ID: "509", Name: "Phones & Telecommunications", Level: 1},
+		{ID: "44", Name: "Consumer Electronics", Level: 1},
+		{ID: "5090301", ParentID: "509", Name: "Mobile Phones", Level: 2},
+		{ID: "63705", ParentID: "44", Name: "Earphones & Headphones", Level: 2},
+	}, nil
+}
+
+// FetchHotProducts returns the mock products with a fixed original price.
+func (m *MockAlibabaGateway) FetchHotProducts(ctx context.Context, filters domain.DealsFilters, pageNo, pageSize int) (*domain.ProductPage, error) {
+	page, _ := m.FetchProducts(ctx, domain.SearchIntent{}, pageNo, pageSize)
+	for _, p := range page.Products {
+		p.OriginalPrice = &domain.Price{USD: p.Price.USD * 2}
+		p.Discount = 50
+	}
+	return page, nil
+}
+
+// GenerateAffiliateLinks returns a fixed mock affiliate link per source URL.
+func (m *MockAlibabaGateway) GenerateAffiliateLinks(ctx context.Context, sourceURLs []string) (map[string]string, error) {
+	links := make(map[string]string, len(sourceURLs))
+	for i, src := range sourceURLs {
+		links[src] = fmt.Sprintf("https://s.click.aliexpress.com/e/_mock%d", i)
+	}
+	return links, nil
 }
@@ -64,14 +64,22 @@ func NewMockLLMGateway() domain.LLMGateway {
 	return &MockLLMGateway{}
 }
 
-func (m *MockLLMGateway) ParseIntent(ctx context.Context, query string) (map[string]interface{}, error) {
+func (m *MockLLMGateway) ParseIntent(ctx context.Context, query string) (*domain.SearchIntent, error) {
 	// Very simple mocked intent
-	return map[string]interface{}{
-		"category":      "smartphone",
-		"price_max_ETB": 5000,
+	maxPrice := 5000.0
+	return &domain.SearchIntent{
+		Keywords:      "smartphone",
+		MaxPrice:      &maxPrice,
+		Currency:      "ETB",
+		ShipToCountry: domain.DefaultShipToCountry,
 	}, nil
 }
 
+// RefineIntent returns an empty delta, repeating the previous search.
+func (m *MockLLMGateway) RefineIntent(ctx context.Context, previous domain.SearchIntent, seenIDs []string, followUp string) (*domain.IntentDelta, error) {
+	return &domain.IntentDelta{}, nil
+}
+
 // SummarizeProduct returns a mocked summarized product for testing
 func (m *MockLLMGateway) SummarizeProduct(ctx context.Context, p *domain.Product, summaryType string) (*domain.Product, error) {
 	// Return a copy of the product with a mocked summary field if needed
@@ -79,3 +87,10 @@ func (m *MockLLMGateway) SummarizeProduct(ctx context.Context, p *domain.Product
 	// You can add a mocked summary to a field if domain.Product has one, or just return the product as is
 	return &mockedProduct, nil
 }
+
+// FallbackSummary returns an unenriched copy of the product.
+func (m *MockLLMGateway) FallbackSummary(ctx context.Context, p *domain.Product, userPrompt string) *domain.Product {
+	fallback := *p
+	fallback.AIEnriched = false
+	return &fallback
+}
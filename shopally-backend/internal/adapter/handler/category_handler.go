@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// CategoryHandler serves the synced AliExpress category tree.
+type CategoryHandler struct {
+	catalog *usecase.CategoryCatalog
+}
+
+// NewCategoryHandler creates a new CategoryHandler.
+func NewCategoryHandler(catalog *usecase.CategoryCatalog) *CategoryHandler {
+	return &CategoryHandler{catalog: catalog}
+}
+
+// ListCategories handles GET /categories. Without parameters it returns the
+// top-level categories; with parentId it returns that category's children.
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	parentID := strings.TrimSpace(c.Query("parentId"))
+	if parentID != "" && !productIDRe.MatchString(parentID) {
+		c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INVALID_INPUT",
+			"message": "invalid parentId",
+		}})
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{
+		"categories": h.catalog.List(parentID),
+	}, Error: nil})
+}
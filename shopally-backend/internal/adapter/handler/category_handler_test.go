@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryHandler_ListCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	catalog := usecase.NewCategoryCatalog(nil, nil)
+	catalog.Load([]domain.Category{
+		{ID: "44", Name: "Consumer Electronics", Level: 1},
+		{ID: "63705", ParentID: "44", Name: "Earphones & Headphones", Level: 2},
+	})
+	router := gin.New()
+	router.GET("/categories", NewCategoryHandler(catalog).ListCategories)
+
+	get := func(path string) (int, []domain.Category) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+		var body struct {
+			Data struct {
+				Categories []domain.Category `json:"categories"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body.Data.Categories
+	}
+
+	code, top := get("/categories")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, top, 1)
+	assert.Equal(t, "44", top[0].ID)
+
+	_, children := get("/categories?parentId=44")
+	require.Len(t, children, 1)
+	assert.Equal(t, "63705", children[0].ID)
+
+	code, _ = get("/categories?parentId=a%20b")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
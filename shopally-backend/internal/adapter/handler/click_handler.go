@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+const (
+	// clickRecordTimeout bounds how long a redirect waits for its click to be stored.
+	clickRecordTimeout = 2 * time.Second
+	// maxDeviceIDLength bounds client-supplied device IDs.
+	maxDeviceIDLength = 128
+)
+
+// ClickHandler serves tracked product links.
+type ClickHandler struct {
+	uc *usecase.TrackClickUseCase
+}
+
+// NewClickHandler creates a new ClickHandler.
+func NewClickHandler(uc *usecase.TrackClickUseCase) *ClickHandler {
+	return &ClickHandler{uc: uc}
+}
+
+// Redirect handles GET /r/:token. It records the click against the device
+// in the X-Device-ID header or, failing that, the device query parameter,
+// then redirects to the affiliate link. Tokens name no device and a browser
+// sends no X-Device-ID, so clients must append device=<their device ID> to a
+// tracked link before opening it; otherwise the click has no device.
+func (h *ClickHandler) Redirect(c *gin.Context) {
+	device := deviceID(c)
+	if device == "" {
+		if q := strings.TrimSpace(c.Query("device")); len(q) <= maxDeviceIDLength {
+			device = q
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), clickRecordTimeout)
+	defer cancel()
+	target, err := h.uc.Execute(ctx, c.Param("token"), device, c.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidClickToken) {
+			c.JSON(http.StatusNotFound, envelope{Data: nil, Error: map[string]interface{}{
+				"code":    "NOT_FOUND",
+				"message": "link not found",
+			}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		}})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target)
+}
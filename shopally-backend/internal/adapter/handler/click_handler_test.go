@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHandler_Redirect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	linker := usecase.NewAffiliateLinker(gateway.NewMockAlibabaGateway(), nil, "secret")
+	products := []*domain.Product{{ID: "42", DeeplinkURL: "https://www.aliexpress.com/item/42.html"}}
+	linker.Apply(context.Background(), products, domain.ClickSurfaceSearch)
+	require.True(t, strings.HasPrefix(products[0].DeeplinkURL, "/r/"))
+
+	repo := mocks.NewClickRepository(t)
+	repo.On("RecordClick", mock.Anything, mock.MatchedBy(func(c domain.Click) bool {
+		return c.DeviceID == "device-1" && c.ProductID == "42" && c.Surface == domain.ClickSurfaceSearch
+	})).Return(nil).Once()
+	router := gin.New()
+	router.GET("/r/:token", NewClickHandler(usecase.NewTrackClickUseCase(repo, linker)).Redirect)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, products[0].DeeplinkURL, nil)
+	req.Header.Set("X-Device-ID", "device-1")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://s.click.aliexpress.com/e/_mock0", w.Header().Get("Location"))
+
+	// A browser opening the link sends no header; the client appends the device.
+	repo.On("RecordClick", mock.Anything, mock.MatchedBy(func(c domain.Click) bool {
+		return c.DeviceID == "device-2" && c.ProductID == "42"
+	})).Return(nil).Once()
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, products[0].DeeplinkURL+"?device=device-2", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/r/forged.token", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// maxPromotionNameLength bounds the promotionName filter.
+const maxPromotionNameLength = 64
+
+// dealsParams are the query parameters GET /deals accepts. Deals are always
+// ranked as best deals, so the search-only sort and maxDeliveryDays filters
+// are rejected rather than ignored.
+var dealsParams = map[string]bool{
+	"cursor": true, "page": true, "pageSize": true,
+	"categoryId": true, "minPrice": true, "maxPrice": true, "currency": true,
+	"promotionName": true,
+}
+
+// DealsHandler serves the hot-deals feed.
+type DealsHandler struct {
+	uc *usecase.GetDealsUseCase
+}
+
+// NewDealsHandler creates a new DealsHandler.
+func NewDealsHandler(uc *usecase.GetDealsUseCase) *DealsHandler {
+	return &DealsHandler{uc: uc}
+}
+
+// GetDeals handles GET /deals. It accepts the search price filters,
+// categoryId and promotionName, and paginates with page/pageSize or cursor;
+// any other parameter is rejected.
+func (h *DealsHandler) GetDeals(c *gin.Context) {
+	badRequest := func(msg string) {
+		c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INVALID_INPUT",
+			"message": msg,
+		}})
+	}
+
+	for name := range c.Request.URL.Query() {
+		if !dealsParams[name] {
+			badRequest("unsupported parameter: " + name)
+			return
+		}
+	}
+
+	var req domain.DealsRequest
+	if cursor := strings.TrimSpace(c.Query("cursor")); cursor != "" {
+		page, size, err := usecase.DecodePageCursor(cursor)
+		if err != nil {
+			badRequest("invalid cursor")
+			return
+		}
+		req.Page, req.PageSize = page, size
+	} else {
+		var ok bool
+		if req.Page, ok = parsePositiveInt(c.Query("page"), 0); !ok {
+			badRequest("page must be a positive integer")
+			return
+		}
+		if req.PageSize, ok = parsePositiveInt(c.Query("pageSize"), usecase.MaxPageSize); !ok {
+			badRequest(fmt.Sprintf("pageSize must be an integer between 1 and %d", usecase.MaxPageSize))
+			return
+		}
+		if size := cmp.Or(req.PageSize, usecase.DefaultPageSize); req.Page > usecase.LastPage(size) {
+			badRequest(fmt.Sprintf("page must not exceed %d for pageSize %d", usecase.LastPage(size), size))
+			return
+		}
+	}
+
+	filters, err := parseDealsFilters(c)
+	if err != nil {
+		badRequest(err.Error())
+		return
+	}
+	req.Filters = filters
+
+	data, err := h.uc.Execute(responseContext(c), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: data, Error: nil})
+}
+
+// parseDealsFilters parses the deals filters: the search price filters,
+// categoryId and promotionName.
+func parseDealsFilters(c *gin.Context) (domain.DealsFilters, error) {
+	var f domain.DealsFilters
+	var err error
+	if f.MinPrice, f.MaxPrice, f.Currency, err = parsePriceFilters(c); err != nil {
+		return f, err
+	}
+	f.CategoryID = strings.TrimSpace(c.Query("categoryId"))
+	f.PromotionName = strings.TrimSpace(c.Query("promotionName"))
+	if len(f.PromotionName) > maxPromotionNameLength {
+		return f, fmt.Errorf("invalid promotionName")
+	}
+	return f, nil
+}
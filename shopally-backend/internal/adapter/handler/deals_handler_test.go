@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealsHandler_GetDeals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDealsHandler(usecase.NewGetDealsUseCase(gateway.NewMockAlibabaGateway(), nil, nil))
+	router := gin.New()
+	router.GET("/deals", h.GetDeals)
+
+	cases := map[string]struct {
+		path string
+		code int
+	}{
+		"default":        {"/deals", http.StatusOK},
+		"filtered":       {"/deals?categoryId=44&maxPrice=100&currency=USD&promotionName=Hot%20Product", http.StatusOK},
+		"bad cursor":     {"/deals?cursor=nope", http.StatusBadRequest},
+		"bad price":      {"/deals?minPrice=-1", http.StatusBadRequest},
+		"bad pageSize":   {"/deals?pageSize=0", http.StatusBadRequest},
+		"page past pool": {"/deals?page=51", http.StatusBadRequest},
+		"long promotion": {"/deals?promotionName=" + strings.Repeat("x", maxPromotionNameLength+1), http.StatusBadRequest},
+		"sort":           {"/deals?sort=cheapest", http.StatusBadRequest},
+		"delivery":       {"/deals?maxDeliveryDays=7", http.StatusBadRequest},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/deals?pageSize=1", nil)
+	router.ServeHTTP(w, req)
+	var body struct {
+		Data struct {
+			Products []struct {
+				ID            string `json:"id"`
+				OriginalPrice *struct {
+					USD float64 `json:"usd"`
+				} `json:"originalPrice"`
+			} `json:"products"`
+			Page struct {
+				PageSize int `json:"pageSize"`
+			} `json:"page"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data.Products, 1)
+	assert.NotNil(t, body.Data.Products[0].OriginalPrice)
+	assert.Equal(t, 1, body.Data.Page.PageSize)
+}
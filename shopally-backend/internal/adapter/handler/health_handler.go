@@ -8,11 +8,37 @@ import (
 	"github.com/shopally-ai/pkg/domain"
 )
 
-func Health(c *gin.Context) {
+// HealthCheck reports the state of a dependency and whether the API is
+// degraded by it.
+type HealthCheck func() (status interface{}, degraded bool)
+
+// HealthHandler serves the health endpoint.
+type HealthHandler struct {
+	checks map[string]HealthCheck
+}
+
+// NewHealthHandler creates a new HealthHandler reporting checks by name.
+func NewHealthHandler(checks map[string]HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Health handles GET /health. It always answers 200 while the API is up;
+// status is "degraded" when a dependency reports so.
+func (h *HealthHandler) Health(c *gin.Context) {
+	status := "ok"
+	components := make(map[string]interface{}, len(h.checks))
+	for name, check := range h.checks {
+		s, degraded := check()
+		components[name] = s
+		if degraded {
+			status = "degraded"
+		}
+	}
 	c.JSON(http.StatusOK, domain.Response{
 		Data: map[string]interface{}{
-			"status": "ok",
-			"time":   time.Now(),
+			"status":     status,
+			"time":       time.Now(),
+			"components": components,
 		},
 		Error: nil,
 	})
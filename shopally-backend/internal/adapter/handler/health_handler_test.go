@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	degraded := false
+	h := NewHealthHandler(map[string]HealthCheck{
+		"aliexpress": func() (interface{}, bool) {
+			return map[string]string{"state": "open"}, degraded
+		},
+	})
+	router := gin.New()
+	router.GET("/health", h.Health)
+
+	get := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	assert.Equal(t, "ok", get()["status"])
+	degraded = true
+	data := get()
+	assert.Equal(t, "degraded", data["status"])
+	assert.Equal(t, map[string]interface{}{"aliexpress": map[string]interface{}{"state": "open"}}, data["components"])
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// LandedCostHandler handles HTTP requests for standalone landed-cost estimates.
+type LandedCostHandler struct {
+	uc *usecase.EstimateLandedCostUseCase
+}
+
+// NewLandedCostHandler creates a new LandedCostHandler with its dependencies.
+func NewLandedCostHandler(uc *usecase.EstimateLandedCostUseCase) *LandedCostHandler {
+	return &LandedCostHandler{uc: uc}
+}
+
+// Estimate handles POST /landed-cost and returns the ETB landed-cost breakdown
+// for the item described by the body.
+func (h *LandedCostHandler) Estimate(c *gin.Context) {
+	var req domain.LandedCostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INVALID_INPUT",
+			"message": "Invalid request body. Ensure it is valid JSON.",
+		}})
+		return
+	}
+
+	data, err := h.uc.Execute(c.Request.Context(), req)
+	if errors.Is(err, usecase.ErrInvalidLandedCostRequest) {
+		c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INVALID_INPUT",
+			"message": err.Error(),
+		}})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		}})
+		return
+	}
+
+	c.JSON(http.StatusOK, envelope{Data: data, Error: nil})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLandedCostHandler_Estimate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calc := usecase.NewLandedCostCalculator(usecase.DefaultLandedCostSettings())
+	h := NewLandedCostHandler(usecase.NewEstimateLandedCostUseCase(calc, nil))
+	router := gin.New()
+	router.POST("/landed-cost", h.Estimate)
+
+	cases := map[string]struct {
+		body string
+		code int
+	}{
+		"valid":          {`{"priceUsd": 20, "shippingUsd": 3, "categoryIds": ["44"]}`, http.StatusOK},
+		"missing price":  {`{"shippingUsd": 3}`, http.StatusBadRequest},
+		"malformed json": {`{"priceUsd":`, http.StatusBadRequest},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/landed-cost", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+			if tc.code == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"totalUsd"`)
+				assert.Contains(t, w.Body.String(), `"fxDegraded":true`)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// PriceHistoryHandler serves the recorded price history of products.
+type PriceHistoryHandler struct {
+	uc *usecase.GetPriceHistoryUseCase
+}
+
+// NewPriceHistoryHandler creates a new PriceHistoryHandler.
+func NewPriceHistoryHandler(uc *usecase.GetPriceHistoryUseCase) *PriceHistoryHandler {
+	return &PriceHistoryHandler{uc: uc}
+}
+
+// GetPriceHistory handles GET /products/:id/price-history. The optional from
+// and to parameters are YYYY-MM-DD dates; both days are included.
+func (h *PriceHistoryHandler) GetPriceHistory(c *gin.Context) {
+	id := c.Param("id")
+	if !productIDRe.MatchString(id) {
+		writeInvalidInput(c, "invalid product id")
+		return
+	}
+	parseDate := func(name string) (time.Time, bool) {
+		raw := strings.TrimSpace(c.Query(name))
+		if raw == "" {
+			return time.Time{}, true
+		}
+		d, err := time.Parse("2006-01-02", raw)
+		return d, err == nil
+	}
+	from, ok := parseDate("from")
+	if !ok {
+		writeInvalidInput(c, "from must be a YYYY-MM-DD date")
+		return
+	}
+	to, ok := parseDate("to")
+	if !ok {
+		writeInvalidInput(c, "to must be a YYYY-MM-DD date")
+		return
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	data, err := h.uc.Execute(c.Request.Context(), id, from, to)
+	if errors.Is(err, usecase.ErrInvalidDateRange) {
+		writeInvalidInput(c, "from must be before to and the range at most a year")
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		}})
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: data, Error: nil})
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewProductRepository(t)
+	router := gin.New()
+	router.GET("/products/:id/price-history", NewPriceHistoryHandler(usecase.NewGetPriceHistoryUseCase(repo)).GetPriceHistory)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, get("/products/42/price-history?from=01-03-2025").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/products/42/price-history?from=2025-03-05&to=2025-03-01").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/products/a%20b/price-history").Code)
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo.On("PricePoints", mock.Anything, "42", from, from.AddDate(0, 0, 2)).
+		Return([]domain.PricePoint{{USD: 9.5, At: from.Add(time.Hour)}}, nil).Once()
+	w := get("/products/42/price-history?from=2025-03-01&to=2025-03-02")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data domain.PriceHistory `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data.Days, 1)
+	assert.Equal(t, "2025-03-01", body.Data.Days[0].Date)
+	assert.Equal(t, 9.5, body.Data.Days[0].CloseUSD)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+var productIDRe = regexp.MustCompile(`^[A-Za-z0-9-]{1,64}$`)
+
+// ProductHandler handles incoming HTTP requests for single products.
+type ProductHandler struct {
+	uc    *usecase.GetProductDetailUseCase
+	views *usecase.ProductViewsUseCase
+}
+
+// NewProductHandler creates a new ProductHandler with its dependencies. views
+// may be nil to disable implicit view tracking.
+func NewProductHandler(uc *usecase.GetProductDetailUseCase, views *usecase.ProductViewsUseCase) *ProductHandler {
+	return &ProductHandler{uc: uc, views: views}
+}
+
+// GetProduct handles GET /products/:id. With summary=true the product carries
+// LLM summary bullets in the Accept-Language language. A successful fetch
+// counts as a view by the X-Device-ID device.
+func (h *ProductHandler) GetProduct(c *gin.Context) {
+	id := c.Param("id")
+	if !productIDRe.MatchString(id) {
+		c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INVALID_INPUT",
+			"message": "invalid product id",
+		}})
+		return
+	}
+	withSummary := false
+	if raw := c.Query("summary"); raw != "" {
+		var err error
+		if withSummary, err = strconv.ParseBool(raw); err != nil {
+			c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
+				"code":    "INVALID_INPUT",
+				"message": "summary must be true or false",
+			}})
+			return
+		}
+	}
+
+	data, err := h.uc.Execute(responseContext(c), id, withSummary)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		c.JSON(http.StatusNotFound, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "NOT_FOUND",
+			"message": err.Error(),
+		}})
+		return
+	}
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if device := deviceID(c); h.views != nil && device != "" {
+		if err := h.views.RecordView(c.Request.Context(), device, id); err != nil {
+			log.Printf("ProductHandler: failed to record view of %s: %v", id, err)
+		}
+	}
+	c.JSON(http.StatusOK, envelope{Data: data, Error: nil})
+}
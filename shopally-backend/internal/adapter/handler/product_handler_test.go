@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProductRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	uc := usecase.NewGetProductDetailUseCase(gateway.NewMockAlibabaGateway(), gateway.NewMockLLMGateway(), nil, nil)
+	h := NewProductHandler(uc, nil)
+	router := gin.New()
+	router.GET("/products/:id", h.GetProduct)
+	return router
+}
+
+func TestProductHandler_GetProduct(t *testing.T) {
+	router := newTestProductRouter()
+
+	cases := map[string]struct {
+		path string
+		code int
+	}{
+		"found":       {"/products/MOCK-123?summary=true", http.StatusOK},
+		"not found":   {"/products/42", http.StatusNotFound},
+		"invalid id":  {"/products/abc%20def", http.StatusBadRequest},
+		"bad summary": {"/products/MOCK-123?summary=maybe", http.StatusBadRequest},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/products/MOCK-123", nil)
+	router.ServeHTTP(w, req)
+	var body struct {
+		Data struct {
+			Product struct {
+				ID     string   `json:"id"`
+				Images []string `json:"images"`
+			} `json:"product"`
+			FXDegraded bool `json:"fxDegraded"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "MOCK-123", body.Data.Product.ID)
+	assert.NotEmpty(t, body.Data.Product.Images)
+	assert.True(t, body.Data.FXDegraded)
+}
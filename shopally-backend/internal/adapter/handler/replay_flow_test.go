@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cannedAliProducts = `{"aliexpress_affiliate_product_query_response":{"resp_result":{"result":{
+	"current_record_count":2,"total_record_count":2,"current_page_no":1,
+	"products":{"product":[
+		{"product_id":1001,"product_title":"Wireless Earbuds","target_sale_price":"19.90","target_original_price":"39.80","discount":"50%","evaluate_rate":"95.0%","lastest_volume":320,"product_main_image_url":"https://img.test/1.jpg","product_detail_url":"https://www.aliexpress.com/item/1001.html","shop_name":"Audio Store"},
+		{"product_id":1002,"product_title":"Bluetooth Headphones","target_sale_price":"29.50","target_original_price":"29.50","evaluate_rate":"91.0%","lastest_volume":120,"product_main_image_url":"https://img.test/2.jpg","product_detail_url":"https://www.aliexpress.com/item/1002.html","shop_name":"Sound Shop"}
+	]}}}}}`
+
+// cannedUpstream answers AliExpress, Gemini and FX calls like the real APIs would.
+func cannedUpstream(r *http.Request) (*http.Response, error) {
+	var body string
+	switch {
+	case strings.Contains(r.URL.Host, "aliexpress"):
+		body = cannedAliProducts
+	case strings.Contains(r.URL.Host, "fx"):
+		body = `{"result":155.5}`
+	default:
+		raw, _ := io.ReadAll(r.Body)
+		prompt := string(raw)
+		var text string
+		switch {
+		case strings.Contains(prompt, "search intent parser"):
+			text = `{"keywords":"earbuds","max_sale_price":50,"is_etb":false}`
+		case strings.Contains(prompt, "product content enhancer"):
+			text = `{"description":"Great sound.","summaryBullets":["• Long battery","• Clear audio"]}`
+		case strings.Contains(prompt, "compares e-commerce products"):
+			text = `{"comparison":[{"synthesis":{"pros":["cheaper"],"isBestValue":true}},{"synthesis":{"pros":["over-ear"],"isBestValue":false}}]}`
+		}
+		out, _ := json.Marshal(map[string]interface{}{
+			"candidates": []interface{}{map[string]interface{}{
+				"content": map[string]interface{}{"parts": []interface{}{map[string]string{"text": text}}},
+			}},
+		})
+		body = string(out)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (f transportFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// newFixtureRouter wires /search and /compare to real gateways sending
+// through a fixture transport.
+func newFixtureRouter(t *testing.T, mode gateway.FixtureMode, dir string, next http.RoundTripper) *gin.Engine {
+	tr, err := gateway.NewFixtureTransport(mode, dir, next)
+	require.NoError(t, err)
+	tr.Redact = []string{"ali-key", "ali-secret", "gemini-key", "fx-key"}
+
+	cfg := &config.Config{}
+	cfg.Aliexpress.BaseURL = "https://api-sg.aliexpress.test/sync"
+	cfg.Aliexpress.AppKey, cfg.Aliexpress.AppSecret = "ali-key", "ali-secret"
+
+	fx := gateway.NewFXHTTPGateway("https://fx.test/convert?access_key=fx-key", "fx-key", tr.Client(time.Second))
+	llm := gateway.NewGeminiLLMGateway("gemini-key", fx, tr.Client(time.Second))
+	ag := gateway.NewAlibabaHTTPGateway(cfg, tr.Client(time.Second))
+
+	search := NewSearchHandler(usecase.NewSearchProductsUseCase(ag, llm, nil, fx), nil)
+	compare := NewCompareHandler(usecase.NewCompareProductsUseCase(llm, fx))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/search", search.Search)
+	router.POST("/compare", compare.CompareProducts)
+	return router
+}
+
+// runFlow searches, then compares the first two results.
+func runFlow(t *testing.T, router *gin.Engine) (searchData, compareData interface{}) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/search?q=earbuds+under+50+dollars", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var search struct {
+		Data struct {
+			Products []map[string]interface{} `json:"products"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &search))
+	require.Len(t, search.Data.Products, 2)
+
+	body, _ := json.Marshal(map[string]interface{}{"products": search.Data.Products})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/compare", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var compare envelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &compare))
+	return search.Data.Products, compare.Data
+}
+
+// withoutFetchTimes drops the FX timestamps, which record when this process
+// fetched the rate rather than anything served by upstream.
+func withoutFetchTimes(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		delete(x, "fxTimestamp")
+		for _, e := range x {
+			withoutFetchTimes(e)
+		}
+	case []map[string]interface{}:
+		for _, e := range x {
+			withoutFetchTimes(e)
+		}
+	case []interface{}:
+		for _, e := range x {
+			withoutFetchTimes(e)
+		}
+	}
+	return v
+}
+
+func TestSearchAndCompare_ReplayFixturesOffline(t *testing.T) {
+	dir := t.TempDir()
+	recordedSearch, recordedCompare := runFlow(t, newFixtureRouter(t, gateway.FixtureRecord, dir, transportFunc(cannedUpstream)))
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NotEmpty(t, files)
+	for _, f := range files {
+		raw, _ := os.ReadFile(f)
+		for _, secret := range []string{"ali-key", "ali-secret", "gemini-key", "fx-key", "sign="} {
+			assert.NotContains(t, string(raw), secret, filepath.Base(f))
+		}
+	}
+
+	offline := transportFunc(func(r *http.Request) (*http.Response, error) {
+		t.Errorf("network call in replay mode: %s", r.URL.Host)
+		return nil, errors.New("network disabled")
+	})
+	replayedSearch, replayedCompare := runFlow(t, newFixtureRouter(t, gateway.FixtureReplay, dir, offline))
+
+	assert.Equal(t, withoutFetchTimes(recordedSearch), withoutFetchTimes(replayedSearch))
+	assert.Equal(t, withoutFetchTimes(recordedCompare), withoutFetchTimes(replayedCompare))
+	assert.Contains(t, recordedCompare, "comparison")
+}
@@ -1,43 +1,156 @@
 package handler
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
 	"github.com/shopally-ai/pkg/usecase"
 )
 
 // SearchHandler handles incoming HTTP requests for the /search endpoint.
 type SearchHandler struct {
-	uc *usecase.SearchProductsUseCase
+	uc     *usecase.SearchProductsUseCase
+	policy *usecase.ContentPolicy
 }
 
-// NewSearchHandler creates a new SearchHandler with its dependencies.
-func NewSearchHandler(uc *usecase.SearchProductsUseCase) *SearchHandler {
-	return &SearchHandler{uc: uc}
+// NewSearchHandler creates a new SearchHandler with its dependencies. policy
+// may be nil to disable query moderation.
+func NewSearchHandler(uc *usecase.SearchProductsUseCase, policy *usecase.ContentPolicy) *SearchHandler {
+	return &SearchHandler{uc: uc, policy: policy}
 }
 
+// maxSessionIDLength bounds client-supplied session IDs (issued as UUIDs or
+// search cache keys).
+const maxSessionIDLength = 80
+
 type envelope struct {
 	Data  interface{} `json:"data"`
 	Error interface{} `json:"error"`
 }
 
-// Search handles GET /search and returns the envelope with mocked products.
+// Search handles GET /search and returns the envelope with the search result.
 func (h *SearchHandler) Search(c *gin.Context) {
-	// Basic required param validation per contract
-	q := strings.TrimSpace(c.Query("q"))
-	if q == "" {
+	ctx, req, ok := parseSearchRequest(c)
+	if !ok || !h.allowQuery(c, ctx, req.Query) {
+		return
+	}
+
+	data, err := h.uc.Search(ctx, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envelope{Data: data, Error: nil})
+}
+
+// SearchStream handles GET /search/stream. It accepts the same parameters as
+// Search and streams pipeline progress as Server-Sent Events: "intent",
+// "products", one "product" per enhanced item and a final "done" carrying the
+// full result. Failures are reported as an "error" event.
+func (h *SearchHandler) SearchStream(c *gin.Context) {
+	ctx, req, ok := parseSearchRequest(c)
+	if !ok || !h.allowQuery(c, ctx, req.Query) {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	emit := func(event string, data interface{}) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+
+	if _, err := h.uc.SearchStream(ctx, req, emit); err != nil {
+		_, body := errorBody(err)
+		emit("error", body)
+	}
+}
+
+// allowQuery applies the content policy to the query. Blocked queries get a
+// 403 with code POLICY_BLOCKED and are recorded against the caller's device.
+func (h *SearchHandler) allowQuery(c *gin.Context, ctx context.Context, query string) bool {
+	if h.policy == nil {
+		return true
+	}
+	if err := h.policy.CheckQuery(ctx, c.GetHeader("X-Device-ID"), query); err != nil {
+		c.JSON(http.StatusForbidden, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "POLICY_BLOCKED",
+			"message": err.Error(),
+		}})
+		return false
+	}
+	return true
+}
+
+// parseSearchRequest validates the /search query parameters and builds the
+// request context carrying the response language and currency. On invalid
+// input it writes a 400 response and returns ok=false.
+func parseSearchRequest(c *gin.Context) (context.Context, domain.SearchRequest, bool) {
+	badRequest := func(msg string) (context.Context, domain.SearchRequest, bool) {
 		c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
 			"code":    "INVALID_INPUT",
-			"message": "missing required query parameter: q",
+			"message": msg,
 		}})
-		return
+		return nil, domain.SearchRequest{}, false
+	}
+
+	// Basic required param validation per contract
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		return badRequest("missing required query parameter: q")
+	}
+
+	req := domain.SearchRequest{Query: q}
+	if cursor := strings.TrimSpace(c.Query("cursor")); cursor != "" {
+		page, size, err := usecase.DecodePageCursor(cursor)
+		if err != nil {
+			return badRequest("invalid cursor")
+		}
+		req.Page, req.PageSize = page, size
+	} else {
+		var ok bool
+		if req.Page, ok = parsePositiveInt(c.Query("page"), 0); !ok {
+			return badRequest("page must be a positive integer")
+		}
+		if req.PageSize, ok = parsePositiveInt(c.Query("pageSize"), usecase.MaxPageSize); !ok {
+			return badRequest(fmt.Sprintf("pageSize must be an integer between 1 and %d", usecase.MaxPageSize))
+		}
+		if size := cmp.Or(req.PageSize, usecase.DefaultPageSize); req.Page > usecase.LastPage(size) {
+			return badRequest(fmt.Sprintf("page must not exceed %d for pageSize %d", usecase.LastPage(size), size))
+		}
+	}
+
+	filters, err := parseSearchFilters(c)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+	req.Filters = filters
+
+	if sessionID := strings.TrimSpace(c.Query("sessionId")); sessionID != "" {
+		if len(sessionID) > maxSessionIDLength {
+			return badRequest("invalid sessionId")
+		}
+		req.SessionID = sessionID
 	}
 
-	// get from header
+	return responseContext(c), req, true
+}
+
+// responseContext returns the request context carrying the response language
+// and currency chosen by the Accept-Language header.
+func responseContext(c *gin.Context) context.Context {
 	lang := strings.ToLower(strings.TrimSpace(c.GetHeader("Accept-Language")))
 
 	ctx := c.Request.Context()
@@ -48,20 +161,86 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		ctx = context.WithValue(ctx, contextkeys.RespLang, "en")
 		ctx = context.WithValue(ctx, contextkeys.RespCurrency, "USD")
 	}
+	return ctx
+}
 
-	data, err := h.uc.Search(ctx, q)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, envelope{Data: nil, Error: map[string]interface{}{
-			"code":    "INTERNAL_SERVER_ERROR",
-			"message": err.Error(),
-		}})
-		return
+// parseSearchFilters reads the optional explicit filter parameters of /search.
+// minPrice and maxPrice are in currency, or USD when it is omitted.
+func parseSearchFilters(c *gin.Context) (domain.SearchFilters, error) {
+	var f domain.SearchFilters
+	var err error
+	if f.MinPrice, f.MaxPrice, f.Currency, err = parsePriceFilters(c); err != nil {
+		return f, err
 	}
 
-	c.JSON(http.StatusOK, envelope{Data: data, Error: nil})
+	if raw := c.Query("maxDeliveryDays"); strings.TrimSpace(raw) != "" {
+		days, ok := parsePositiveInt(raw, 0)
+		if !ok {
+			return f, fmt.Errorf("maxDeliveryDays must be a positive integer")
+		}
+		f.MaxDeliveryDays = &days
+	}
+
+	f.CategoryID = strings.TrimSpace(c.Query("categoryId"))
+
+	if sort := strings.TrimSpace(c.Query("sort")); sort != "" {
+		if !usecase.IsValidSort(sort) {
+			return f, fmt.Errorf("unsupported sort: %s", sort)
+		}
+		f.Sort = sort
+	}
+	return f, nil
+}
+
+// parsePriceFilters parses the minPrice, maxPrice and currency parameters.
+func parsePriceFilters(c *gin.Context) (minPrice, maxPrice *float64, currency string, err error) {
+	parsePrice := func(name string) (*float64, error) {
+		raw := strings.TrimSpace(c.Query(name))
+		if raw == "" {
+			return nil, nil
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative number", name)
+		}
+		return &v, nil
+	}
+
+	if minPrice, err = parsePrice("minPrice"); err != nil {
+		return nil, nil, "", err
+	}
+	if maxPrice, err = parsePrice("maxPrice"); err != nil {
+		return nil, nil, "", err
+	}
+	if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+		return nil, nil, "", fmt.Errorf("minPrice must not exceed maxPrice")
+	}
+
+	if cur := strings.ToUpper(strings.TrimSpace(c.Query("currency"))); cur != "" {
+		if !usecase.IsValidCurrency(cur) {
+			return nil, nil, "", fmt.Errorf("currency must be USD or ETB")
+		}
+		currency = cur
+	}
+	return minPrice, maxPrice, currency, nil
+}
+
+// parsePositiveInt parses an optional positive integer query value. An empty
+// value yields 0; max <= 0 means no upper bound.
+func parsePositiveInt(raw string, max int) (int, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || (max > 0 && n > max) {
+		return 0, false
+	}
+	return n, true
 }
 
 // RegisterRoutes sets up the routing for the search handler using Gin.
 func (h *SearchHandler) RegisterRoutes(router *gin.Engine) {
 	router.GET("/search", h.Search)
+	router.GET("/search/stream", h.SearchStream)
 }
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSearchRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	uc := usecase.NewSearchProductsUseCase(gateway.NewMockAlibabaGateway(), gateway.NewMockLLMGateway(), nil, nil)
+	h := NewSearchHandler(uc, usecase.NewContentPolicy(nil, nil))
+	router := gin.New()
+	router.GET("/search", h.Search)
+	router.GET("/search/stream", h.SearchStream)
+	return router
+}
+
+func TestSearchHandler_Validation(t *testing.T) {
+	router := newTestSearchRouter()
+
+	cases := map[string]string{
+		"missing query":     "/search",
+		"bad cursor":        "/search?q=phone&cursor=not-a-cursor",
+		"page size too big": "/search?q=phone&pageSize=500",
+		"page past pool":    "/search?q=phone&page=288230376151711745&pageSize=50",
+		"forged cursor":     "/search?q=phone&cursor=" + usecase.EncodePageCursor(288230376151711745, 50),
+		"min above max":     "/search?q=phone&minPrice=10&maxPrice=5",
+		"bad currency":      "/search?q=phone&currency=EUR",
+		"bad sort":          "/search?q=phone&sort=random",
+	}
+	for name, url := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "INVALID_INPUT")
+		})
+	}
+}
+
+func TestSearchHandler_Stream(t *testing.T) {
+	router := newTestSearchRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/search/stream?q=phone", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	intent := strings.Index(body, "event:intent")
+	products := strings.Index(body, "event:products")
+	product := strings.Index(body, "event:product\n")
+	done := strings.Index(body, "event:done")
+	assert.True(t, intent >= 0 && intent < products && products < product && product < done, "unexpected event order:\n%s", body)
+}
+
+func TestSearchHandler_PolicyBlocked(t *testing.T) {
+	router := newTestSearchRouter()
+
+	for _, q := range []string{"cheap firearms", "ጠመንጃ ይሸጣል"} {
+		for _, path := range []string{"/search", "/search/stream"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path+"?q="+url.QueryEscape(q), nil)
+			req.Header.Set("X-Device-ID", "device-1")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, path+" "+q)
+			assert.Contains(t, w.Body.String(), "POLICY_BLOCKED")
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// upstreamErrors maps the product source error taxonomy to API responses, in
+// match order: a rate-limited call also matches ErrUpstreamUnavailable.
+var upstreamErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{domain.ErrUpstreamRateLimited, http.StatusServiceUnavailable, "UPSTREAM_RATE_LIMITED"},
+	{domain.ErrUpstreamUnavailable, http.StatusServiceUnavailable, "UPSTREAM_UNAVAILABLE"},
+	{domain.ErrUpstreamAuth, http.StatusBadGateway, "UPSTREAM_AUTH_FAILED"},
+	{domain.ErrUpstreamInvalidRequest, http.StatusBadGateway, "UPSTREAM_REJECTED"},
+	{domain.ErrUpstreamBadResponse, http.StatusBadGateway, "UPSTREAM_BAD_RESPONSE"},
+}
+
+// errorBody returns the status and error object for err. Product source
+// errors get their own code and a generic message, since upstream details
+// (codes, signatures) mean nothing to clients; anything else is an
+// INTERNAL_SERVER_ERROR.
+func errorBody(err error) (int, map[string]interface{}) {
+	for _, u := range upstreamErrors {
+		if errors.Is(err, u.err) {
+			log.Printf("upstream error %s: %v", u.code, err)
+			return u.status, map[string]interface{}{"code": u.code, "message": u.err.Error()}
+		}
+	}
+	return http.StatusInternalServerError, map[string]interface{}{"code": "INTERNAL_SERVER_ERROR", "message": err.Error()}
+}
+
+// writeError writes err as an error envelope.
+func writeError(c *gin.Context, err error) {
+	status, body := errorBody(err)
+	c.JSON(status, envelope{Data: nil, Error: body})
+}
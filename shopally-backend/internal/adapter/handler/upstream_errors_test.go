@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// rateLimited matches both rate-limit and availability errors, as the
+// gateway's classified errors do.
+type rateLimited struct{}
+
+func (rateLimited) Error() string { return "ApiCallLimit: app signature sk-123 over quota" }
+func (rateLimited) Is(target error) bool {
+	return target == domain.ErrUpstreamRateLimited || target == domain.ErrUpstreamUnavailable
+}
+
+func TestErrorBody(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{rateLimited{}, http.StatusServiceUnavailable, "UPSTREAM_RATE_LIMITED"},
+		{fmt.Errorf("breaker open: %w", domain.ErrUpstreamUnavailable), http.StatusServiceUnavailable, "UPSTREAM_UNAVAILABLE"},
+		{fmt.Errorf("InvalidSignature: %w", domain.ErrUpstreamAuth), http.StatusBadGateway, "UPSTREAM_AUTH_FAILED"},
+		{domain.ErrUpstreamInvalidRequest, http.StatusBadGateway, "UPSTREAM_REJECTED"},
+		{domain.ErrUpstreamBadResponse, http.StatusBadGateway, "UPSTREAM_BAD_RESPONSE"},
+		{errors.New("boom"), http.StatusInternalServerError, "INTERNAL_SERVER_ERROR"},
+	} {
+		status, body := errorBody(tc.err)
+		assert.Equal(t, tc.status, status, tc.code)
+		assert.Equal(t, tc.code, body["code"])
+	}
+
+	_, body := errorBody(rateLimited{})
+	assert.Equal(t, domain.ErrUpstreamRateLimited.Error(), body["message"], "upstream details are not exposed")
+}
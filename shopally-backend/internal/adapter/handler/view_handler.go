@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// ViewHandler records product views and serves a device's recently viewed products.
+type ViewHandler struct {
+	uc *usecase.ProductViewsUseCase
+}
+
+// NewViewHandler creates a new ViewHandler.
+func NewViewHandler(uc *usecase.ProductViewsUseCase) *ViewHandler {
+	return &ViewHandler{uc: uc}
+}
+
+// RecordView handles POST /products/:id/view for the X-Device-ID device.
+func (h *ViewHandler) RecordView(c *gin.Context) {
+	device := deviceID(c)
+	id := c.Param("id")
+	switch {
+	case device == "":
+		writeInvalidInput(c, "missing X-Device-ID header")
+		return
+	case !productIDRe.MatchString(id):
+		writeInvalidInput(c, "invalid product id")
+		return
+	}
+
+	if err := h.uc.RecordView(c.Request.Context(), device, id); err != nil {
+		c.JSON(http.StatusInternalServerError, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		}})
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"recorded": true}, Error: nil})
+}
+
+// RecentlyViewed handles GET /me/recently-viewed and returns the X-Device-ID
+// device's viewed products, newest first, up to ?limit=.
+func (h *ViewHandler) RecentlyViewed(c *gin.Context) {
+	device := deviceID(c)
+	if device == "" {
+		writeInvalidInput(c, "missing X-Device-ID header")
+		return
+	}
+	limit, ok := parsePositiveInt(c.Query("limit"), usecase.MaxRecentlyViewedLimit)
+	if !ok {
+		writeInvalidInput(c, fmt.Sprintf("limit must be an integer between 1 and %d", usecase.MaxRecentlyViewedLimit))
+		return
+	}
+
+	views, err := h.uc.RecentlyViewed(responseContext(c), device, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, envelope{Data: nil, Error: map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		}})
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"items": views}, Error: nil})
+}
+
+// deviceID returns the caller's X-Device-ID, or "" when it is missing or too long.
+func deviceID(c *gin.Context) string {
+	id := strings.TrimSpace(c.GetHeader("X-Device-ID"))
+	if len(id) > maxDeviceIDLength {
+		return ""
+	}
+	return id
+}
+
+func writeInvalidInput(c *gin.Context, msg string) {
+	c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
+		"code":    "INVALID_INPUT",
+		"message": msg,
+	}})
+}
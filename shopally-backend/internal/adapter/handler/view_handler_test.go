@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewViewRepository(t)
+	views := usecase.NewProductViewsUseCase(repo)
+	views.Products = usecase.NewGetProductDetailUseCase(gateway.NewMockAlibabaGateway(), nil, nil, nil)
+	h := NewViewHandler(views)
+
+	router := gin.New()
+	router.POST("/products/:id/view", h.RecordView)
+	router.GET("/me/recently-viewed", h.RecentlyViewed)
+	router.GET("/products/:id", NewProductHandler(views.Products, views).GetProduct)
+
+	do := func(method, path, device string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		if device != "" {
+			req.Header.Set("X-Device-ID", device)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	repo.On("RecordView", mock.Anything, "dev-1", "MOCK-123", mock.AnythingOfType("time.Time")).Return(nil).Twice()
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/products/MOCK-123/view", "dev-1").Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/products/MOCK-123", "dev-1").Code, "detail fetch counts as a view")
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/products/MOCK-123/view", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/products/a%20b/view", "dev-1").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/me/recently-viewed?limit=0", "dev-1").Code)
+
+	repo.On("RecentViews", mock.Anything, "dev-1", 5).
+		Return([]domain.RecentView{{ProductID: "MOCK-123", ViewedAt: time.Now()}}, nil).Once()
+	w := do(http.MethodGet, "/me/recently-viewed?limit=5", "dev-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data struct {
+			Items []domain.RecentView `json:"items"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data.Items, 1)
+	require.NotNil(t, body.Data.Items[0].Product)
+	assert.Equal(t, "MOCK-123", body.Data.Items[0].Product.ID)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCategoryRepository implements domain.CategoryRepository using MongoDB,
+// one document per category keyed by its AliExpress ID.
+type MongoCategoryRepository struct {
+	coll *mongo.Collection
+}
+
+var _ domain.CategoryRepository = (*MongoCategoryRepository)(nil)
+
+// NewMongoCategoryRepository creates a new MongoCategoryRepository.
+func NewMongoCategoryRepository(coll *mongo.Collection) *MongoCategoryRepository {
+	return &MongoCategoryRepository{coll: coll}
+}
+
+// UpsertCategories sets the synced fields of each category in one bulk write.
+// Curated fields (name_am, aliases) are never overwritten.
+func (r *MongoCategoryRepository) UpsertCategories(ctx context.Context, categories []domain.Category) error {
+	if len(categories) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(categories))
+	for _, c := range categories {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": c.ID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"parent_id":  c.ParentID,
+				"name":       c.Name,
+				"level":      c.Level,
+				"updated_at": c.UpdatedAt,
+			}}).
+			SetUpsert(true))
+	}
+	_, err := r.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// DeleteCategoriesBefore removes the categories whose updated_at is before t.
+func (r *MongoCategoryRepository) DeleteCategoriesBefore(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.coll.DeleteMany(ctx, bson.M{"updated_at": bson.M{"$lt": t}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// ListCategories returns every stored category.
+func (r *MongoCategoryRepository) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.Category
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoClickRepository implements domain.ClickRepository using MongoDB, one
+// document per click.
+type MongoClickRepository struct {
+	coll *mongo.Collection
+}
+
+var _ domain.ClickRepository = (*MongoClickRepository)(nil)
+
+// NewMongoClickRepository creates a new MongoClickRepository.
+func NewMongoClickRepository(coll *mongo.Collection) *MongoClickRepository {
+	return &MongoClickRepository{coll: coll}
+}
+
+// RecordClick inserts the click.
+func (r *MongoClickRepository) RecordClick(ctx context.Context, click domain.Click) error {
+	_, err := r.coll.InsertOne(ctx, click)
+	return err
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoPolicyRepository implements domain.PolicyRepository using MongoDB.
+// Terms are read from one collection and blocked attempts appended to another.
+type MongoPolicyRepository struct {
+	terms   *mongo.Collection
+	blocked *mongo.Collection
+}
+
+var _ domain.PolicyRepository = (*MongoPolicyRepository)(nil)
+
+// NewMongoPolicyRepository creates a new MongoPolicyRepository.
+func NewMongoPolicyRepository(terms, blocked *mongo.Collection) *MongoPolicyRepository {
+	return &MongoPolicyRepository{terms: terms, blocked: blocked}
+}
+
+// ListTerms returns every managed policy term.
+func (r *MongoPolicyRepository) ListTerms(ctx context.Context) ([]domain.PolicyTerm, error) {
+	cur, err := r.terms.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.PolicyTerm
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RecordBlocked appends a blocked attempt to the audit collection.
+func (r *MongoPolicyRepository) RecordBlocked(ctx context.Context, attempt domain.BlockedAttempt) error {
+	_, err := r.blocked.InsertOne(ctx, attempt)
+	return err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoProductRepository implements domain.ProductRepository using MongoDB:
+// one snapshot document per product keyed by its ID, and an append-only
+// collection of price points.
+type MongoProductRepository struct {
+	products    *mongo.Collection
+	pricePoints *mongo.Collection
+}
+
+var _ domain.ProductRepository = (*MongoProductRepository)(nil)
+
+// NewMongoProductRepository creates a new MongoProductRepository.
+func NewMongoProductRepository(products, pricePoints *mongo.Collection) *MongoProductRepository {
+	return &MongoProductRepository{products: products, pricePoints: pricePoints}
+}
+
+// EnsureIndexes creates the index price history queries rely on.
+func (r *MongoProductRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.pricePoints.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "at", Value: 1}},
+	})
+	return err
+}
+
+// SaveSnapshots implements domain.ProductRepository.
+func (r *MongoProductRepository) SaveSnapshots(ctx context.Context, products []*domain.Product, points []domain.PricePoint) error {
+	if len(products) > 0 {
+		now := time.Now().UTC()
+		models := make([]mongo.WriteModel, 0, len(products))
+		for _, p := range products {
+			snapshot := bson.M{
+				"title":        p.Title,
+				"image_url":    p.ImageURL,
+				"deeplink_url": p.DeeplinkURL,
+				"price_usd":    p.Price.USD,
+				"discount":     p.Discount,
+				"rating":       p.ProductRating,
+				"number_sold":  p.NumberSold,
+				"categories":   p.Categories,
+				"last_seen_at": now,
+			}
+			if p.OriginalPrice != nil {
+				snapshot["original_price_usd"] = p.OriginalPrice.USD
+			}
+			if p.Shop != nil {
+				snapshot["shop"] = p.Shop
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": p.ID}).
+				SetUpdate(bson.M{
+					"$set":         snapshot,
+					"$setOnInsert": bson.M{"first_seen_at": now},
+				}).
+				SetUpsert(true))
+		}
+		if _, err := r.products.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(points))
+	for _, pt := range points {
+		docs = append(docs, pt)
+	}
+	_, err := r.pricePoints.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	return err
+}
+
+// PricePoints implements domain.ProductRepository.
+func (r *MongoProductRepository) PricePoints(ctx context.Context, productID string, from, to time.Time) ([]domain.PricePoint, error) {
+	cur, err := r.pricePoints.Find(ctx,
+		bson.M{"product_id": productID, "at": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.D{{Key: "at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.PricePoint
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+const (
+	// DefaultViewTrackingTTL is how long a device's view history survives
+	// without a new view.
+	DefaultViewTrackingTTL = 30 * 24 * time.Hour
+	// maxViewsPerDevice bounds the view history kept per device.
+	maxViewsPerDevice = 50
+	// viewBucketTTL is how long hourly view counters are kept.
+	viewBucketTTL = 48 * time.Hour
+)
+
+// RedisViewRepository implements domain.ViewRepository using Redis. Each
+// device has a sorted set of product IDs scored by view time; view counts
+// are kept in one sorted set per hour.
+type RedisViewRepository struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+var _ domain.ViewRepository = (*RedisViewRepository)(nil)
+
+// NewRedisViewRepository creates a new RedisViewRepository. Device histories
+// expire ttl after their last view; ttl <= 0 uses DefaultViewTrackingTTL.
+func NewRedisViewRepository(client *redis.Client, prefix string, ttl time.Duration) *RedisViewRepository {
+	if prefix == "" {
+		prefix = "sa:" //default namespace
+	}
+	if ttl <= 0 {
+		ttl = DefaultViewTrackingTTL
+	}
+	return &RedisViewRepository{client: client, prefix: prefix, ttl: ttl}
+}
+
+func (r *RedisViewRepository) deviceKey(deviceID string) string {
+	return r.prefix + "views:device:" + deviceID
+}
+
+func (r *RedisViewRepository) bucketKey(t time.Time) string {
+	return r.prefix + "views:popular:" + t.UTC().Format("2006010215")
+}
+
+// RecordView adds the view to the device history, trimmed to the newest
+// maxViewsPerDevice products, and to the current hour's counters.
+func (r *RedisViewRepository) RecordView(ctx context.Context, deviceID, productID string, at time.Time) error {
+	device, bucket := r.deviceKey(deviceID), r.bucketKey(at)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, device, redis.Z{Score: float64(at.UnixMilli()), Member: productID})
+		pipe.ZRemRangeByRank(ctx, device, 0, -maxViewsPerDevice-1)
+		pipe.Expire(ctx, device, r.ttl)
+		pipe.ZIncrBy(ctx, bucket, 1, productID)
+		pipe.Expire(ctx, bucket, viewBucketTTL)
+		return nil
+	})
+	return err
+}
+
+// RecentViews implements domain.ViewRepository.
+func (r *RedisViewRepository) RecentViews(ctx context.Context, deviceID string, limit int) ([]domain.RecentView, error) {
+	if limit <= 0 {
+		return []domain.RecentView{}, nil
+	}
+	zs, err := r.client.ZRevRangeWithScores(ctx, r.deviceKey(deviceID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.RecentView, 0, len(zs))
+	for _, z := range zs {
+		id, _ := z.Member.(string)
+		out = append(out, domain.RecentView{ProductID: id, ViewedAt: time.UnixMilli(int64(z.Score)).UTC()})
+	}
+	return out, nil
+}
+
+// ViewCounts sums the hourly counters from the hour containing since to now.
+func (r *RedisViewRepository) ViewCounts(ctx context.Context, productIDs []string, since time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(productIDs) == 0 {
+		return counts, nil
+	}
+	var cmds []*redis.FloatSliceCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		now := time.Now()
+		for t := since.Truncate(time.Hour); !t.After(now); t = t.Add(time.Hour) {
+			cmds = append(cmds, pipe.ZMScore(ctx, r.bucketKey(t), productIDs...))
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	for _, cmd := range cmds {
+		scores, err := cmd.Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, s := range scores {
+			if s > 0 {
+				counts[productIDs[i]] += int(s)
+			}
+		}
+	}
+	return counts, nil
+}
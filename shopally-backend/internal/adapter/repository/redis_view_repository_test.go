@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisViewRepository(t *testing.T) {
+	ctx := context.Background()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	repo := NewRedisViewRepository(client, "", time.Hour)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	require.NoError(t, repo.RecordView(ctx, "dev-1", "A", now.Add(-2*time.Minute)))
+	require.NoError(t, repo.RecordView(ctx, "dev-1", "B", now.Add(-time.Minute)))
+	require.NoError(t, repo.RecordView(ctx, "dev-1", "A", now))
+	require.NoError(t, repo.RecordView(ctx, "dev-2", "B", now))
+	require.NoError(t, repo.RecordView(ctx, "dev-2", "C", now.Add(-3*time.Hour)))
+
+	views, err := repo.RecentViews(ctx, "dev-1", 10)
+	require.NoError(t, err)
+	require.Len(t, views, 2, "repeat views keep one entry")
+	assert.Equal(t, "A", views[0].ProductID)
+	assert.True(t, views[0].ViewedAt.Equal(now))
+	assert.Equal(t, "B", views[1].ProductID)
+	assert.Equal(t, time.Hour, mr.TTL("sa:views:device:dev-1"))
+
+	counts, err := repo.ViewCounts(ctx, []string{"A", "B", "C", "D"}, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"A": 2, "B": 2}, counts)
+
+	for i := 0; i < maxViewsPerDevice+5; i++ {
+		require.NoError(t, repo.RecordView(ctx, "dev-3", string(rune('a'+i)), now.Add(time.Duration(i)*time.Second)))
+	}
+	views, err = repo.RecentViews(ctx, "dev-3", 100)
+	require.NoError(t, err)
+	assert.Len(t, views, maxViewsPerDevice)
+}
@@ -4,7 +4,14 @@ import (
 	"github.com/spf13/viper"
 )
 
+// ProfileDev is the development profile. It enables fallbacks, such as mock
+// AliExpress products, that must never reach production users.
+const ProfileDev = "dev"
+
 type Config struct {
+	// Profile names the deployment profile; empty means production.
+	Profile string `mapstructure:"profile"`
+
 	Server struct {
 		Port string `mapstructure:"port"`
 	} `mapstructure:"server"`
@@ -16,10 +23,11 @@ type Config struct {
 	} `mapstructure:"mongo"`
 
 	Redis struct {
-		Host            string `mapstructure:"host"`
-		Port            string `mapstructure:"port"`
-		Password        string `mapstructure:"password"`
-		DB              int    `mapstructure:"db"`
+		Host     string `mapstructure:"host"`
+		Port     string `mapstructure:"port"`
+		Password string `mapstructure:"password"`
+		DB       int    `mapstructure:"db"`
+		// ViewTrackingTTL is how long, in seconds, a device's view history is kept.
 		ViewTrackingTTL int    `mapstructure:"view_tracking_ttl"`
 		KeyPrefix       string `mapstructure:"key_prefix"`
 	} `mapstructure:"redis"`
@@ -54,11 +62,151 @@ type Config struct {
 		AppKey      string `mapstructure:"app_key"`
 		AppSecret   string `mapstructure:"app_secret"`
 		BaseURL     string `mapstructure:"base_url"`
+		// TrackingID is the affiliate tracking ID commissions are credited to.
+		TrackingID string `mapstructure:"tracking_id"`
+
+		// MaxRetries is how often a call failing with a network, 5xx or
+		// rate-limit error is retried; RetryBaseDelayMillis is the first backoff.
+		MaxRetries           int `mapstructure:"max_retries"`
+		RetryBaseDelayMillis int `mapstructure:"retry_base_delay_millis"`
+		// BreakerThreshold consecutive failed calls open the circuit breaker
+		// for BreakerCooldownSeconds.
+		BreakerThreshold       int `mapstructure:"breaker_threshold"`
+		BreakerCooldownSeconds int `mapstructure:"breaker_cooldown_seconds"`
 	} `mapstructure:"aliexpress"`
 
 	Gemini struct {
 		APIKey string `mapstructure:"api_key"`
 	} `mapstructure:"gemini"`
+
+	Fixtures struct {
+		// Mode is "record" to save AliExpress, Gemini and FX responses to Dir,
+		// or "replay" to serve them from Dir without network access; empty
+		// calls the APIs normally.
+		Mode string `mapstructure:"mode"`
+		Dir  string `mapstructure:"dir"`
+	} `mapstructure:"fixtures"`
+
+	Search struct {
+		CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"`
+		StaleTTLSeconds int `mapstructure:"stale_ttl_seconds"`
+		// FallbackTTLSeconds is how long past its stale window a result is kept
+		// to be served, marked stale, while AliExpress is unavailable.
+		FallbackTTLSeconds int `mapstructure:"fallback_ttl_seconds"`
+
+		SummaryConcurrency    int `mapstructure:"summary_concurrency"`
+		SummaryTimeoutSeconds int `mapstructure:"summary_timeout_seconds"`
+		SummaryBudgetSeconds  int `mapstructure:"summary_budget_seconds"`
+
+		CandidateTarget     int `mapstructure:"candidate_target"`
+		FetchConcurrency    int `mapstructure:"fetch_concurrency"`
+		FetchIntervalMillis int `mapstructure:"fetch_interval_millis"`
+		BackfillPages       int `mapstructure:"backfill_pages"`
+
+		SessionTTLSeconds int `mapstructure:"session_ttl_seconds"`
+
+		ProductCacheTTLSeconds int `mapstructure:"product_cache_ttl_seconds"`
+
+		// Ranking overrides the weights of built-in ranking profiles by name
+		// (best_match, cheapest, fastest, best_rated, most_sold, best_deal, popular).
+		Ranking map[string]RankingWeights `mapstructure:"ranking"`
+	} `mapstructure:"search"`
+
+	Policy struct {
+		// BlockedTerms maps a language ("en", "am") to its blocked terms; an
+		// empty list keeps the built-in defaults for that language.
+		BlockedTerms map[string][]string `mapstructure:"blocked_terms"`
+
+		TermsCollection   string `mapstructure:"terms_collection"`
+		BlockedCollection string `mapstructure:"blocked_collection"`
+		// RefreshIntervalSeconds is how often the API reloads terms from Mongo.
+		RefreshIntervalSeconds int `mapstructure:"refresh_interval_seconds"`
+	} `mapstructure:"policy"`
+
+	Categories struct {
+		Collection string `mapstructure:"collection"`
+		// SyncIntervalSeconds is how often the worker syncs the tree from AliExpress.
+		SyncIntervalSeconds int `mapstructure:"sync_interval_seconds"`
+		// RefreshIntervalSeconds is how often the API reloads the tree from Mongo.
+		RefreshIntervalSeconds int `mapstructure:"refresh_interval_seconds"`
+		// Aliases maps a category name to extra (e.g. Amharic) terms that resolve to it.
+		Aliases map[string][]string `mapstructure:"aliases"`
+	} `mapstructure:"categories"`
+
+	Deals struct {
+		CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"`
+		// PoolPages caps the upstream hot-product pages fetched per filter set.
+		PoolPages int `mapstructure:"pool_pages"`
+		// PrefetchIntervalSeconds is how often the worker refreshes the cached pools.
+		PrefetchIntervalSeconds int `mapstructure:"prefetch_interval_seconds"`
+		// PrefetchCategoryIDs are prefetched in addition to the unfiltered feed.
+		PrefetchCategoryIDs []string `mapstructure:"prefetch_category_ids"`
+	} `mapstructure:"deals"`
+
+	Products struct {
+		Collection           string `mapstructure:"collection"`
+		PricePointCollection string `mapstructure:"price_point_collection"`
+		// PricePointIntervalSeconds is the minimum time between two points of an unchanged price.
+		PricePointIntervalSeconds int `mapstructure:"price_point_interval_seconds"`
+		// MaxPendingWrites caps the background price writes in flight; searches
+		// arriving while it is reached skip recording.
+		MaxPendingWrites int `mapstructure:"max_pending_writes"`
+	} `mapstructure:"products"`
+
+	Views struct {
+		// PopularWindowSeconds is the period whose views feed the "popular now" signal.
+		PopularWindowSeconds int `mapstructure:"popular_window_seconds"`
+	} `mapstructure:"views"`
+
+	Affiliate struct {
+		// LinkSecret signs tracked redirect links; it defaults to the AliExpress app
+		// secret, and the API refuses to start without either.
+		LinkSecret string `mapstructure:"link_secret"`
+		// RedirectBaseURL is the public origin of the /r/:token endpoint, e.g.
+		// "https://api.shopally.et"; empty yields relative links.
+		RedirectBaseURL     string `mapstructure:"redirect_base_url"`
+		LinkCacheTTLSeconds int    `mapstructure:"link_cache_ttl_seconds"`
+		ClickCollection     string `mapstructure:"click_collection"`
+	} `mapstructure:"affiliate"`
+
+	LandedCost struct {
+		// Rates are fractions (0.15 is 15%); unset rates keep the built-in defaults.
+		DutyRate    *float64 `mapstructure:"duty_rate"`
+		ExciseRate  *float64 `mapstructure:"excise_rate"`
+		VATRate     *float64 `mapstructure:"vat_rate"`
+		SurtaxRate  *float64 `mapstructure:"surtax_rate"`
+		ShippingUSD float64  `mapstructure:"shipping_usd"`
+
+		// Rules override duty and excise for AliExpress category IDs.
+		Rules []LandedCostRule `mapstructure:"rules"`
+	} `mapstructure:"landed_cost"`
+}
+
+// RankingWeights are the configured signal weights of one ranking profile;
+// the API maps them onto usecase.RankWeights.
+type RankingWeights struct {
+	Relevance  float64 `mapstructure:"relevance"`
+	Price      float64 `mapstructure:"price"`
+	Delivery   float64 `mapstructure:"delivery"`
+	Rating     float64 `mapstructure:"rating"`
+	Seller     float64 `mapstructure:"seller"`
+	Sales      float64 `mapstructure:"sales"`
+	Discount   float64 `mapstructure:"discount"`
+	Popularity float64 `mapstructure:"popularity"`
+}
+
+// LandedCostRule sets the configured duty and excise rates for a set of
+// category IDs; the API maps it onto usecase.LandedCostRule. An unset rate
+// keeps the default one.
+type LandedCostRule struct {
+	CategoryIDs []string `mapstructure:"category_ids"`
+	Duty        *float64 `mapstructure:"duty"`
+	Excise      *float64 `mapstructure:"excise"`
+}
+
+// IsDev reports whether the development profile is selected.
+func (c *Config) IsDev() bool {
+	return c.Profile == ProfileDev
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Category is an AliExpress product category. Name comes from the category
+// sync; NameAM and Aliases are curated and kept across syncs.
+type Category struct {
+	ID        string    `json:"id" bson:"_id"`
+	ParentID  string    `json:"parentId,omitempty" bson:"parent_id,omitempty"`
+	Name      string    `json:"name" bson:"name"`
+	NameAM    string    `json:"nameAm,omitempty" bson:"name_am,omitempty"`
+	Aliases   []string  `json:"-" bson:"aliases,omitempty"`
+	Level     int       `json:"level" bson:"level"`
+	UpdatedAt time.Time `json:"-" bson:"updated_at"`
+}
+
+// CategoryRepository stores the synced category tree.
+type CategoryRepository interface {
+	// UpsertCategories writes the synced fields of each category, keeping
+	// curated fields of existing documents.
+	UpsertCategories(ctx context.Context, categories []Category) error
+	// DeleteCategoriesBefore removes categories last synced before t and
+	// returns how many were removed.
+	DeleteCategoriesBefore(ctx context.Context, t time.Time) (int64, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+}
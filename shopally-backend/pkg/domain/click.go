@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Click surfaces name where a tracked product link was shown.
+// ClickSurfaceAlert is for product links in alert push notifications; no
+// alert pushes are sent yet, and the alerts worker must issue its links for
+// it once they are.
+const (
+	ClickSurfaceSearch  = "search"
+	ClickSurfaceDeals   = "deals"
+	ClickSurfaceProduct = "product"
+	ClickSurfaceCompare = "compare"
+	ClickSurfaceAlert   = "alert"
+)
+
+// Click is one follow of a tracked product link, recorded before the user is
+// redirected to the affiliate link so commissions can be attributed.
+type Click struct {
+	ID        string `json:"id" bson:"_id"`
+	DeviceID  string `json:"deviceId,omitempty" bson:"device_id,omitempty"`
+	ProductID string `json:"productId" bson:"product_id"`
+	Surface   string `json:"surface" bson:"surface"`
+	TargetURL string `json:"targetUrl" bson:"target_url"`
+	// CommissionRate is the affiliate commission percentage known when the
+	// link was issued; zero when unknown.
+	CommissionRate float64   `json:"commissionRate,omitempty" bson:"commission_rate,omitempty"`
+	UserAgent      string    `json:"userAgent,omitempty" bson:"user_agent,omitempty"`
+	ClickedAt      time.Time `json:"clickedAt" bson:"clicked_at"`
+}
+
+// ClickRepository stores tracked-link clicks.
+type ClickRepository interface {
+	RecordClick(ctx context.Context, click Click) error
+}
@@ -0,0 +1,27 @@
+package domain
+
+// DealsFilters narrows the hot-deals feed. Prices are expressed in Currency
+// ("USD" or "ETB"); gateways receive them in USD.
+type DealsFilters struct {
+	CategoryID    string   `json:"categoryId,omitempty"`
+	MinPrice      *float64 `json:"minPrice,omitempty"`
+	MaxPrice      *float64 `json:"maxPrice,omitempty"`
+	Currency      string   `json:"currency,omitempty"`
+	PromotionName string   `json:"promotionName,omitempty"`
+}
+
+// DealsRequest asks for one page of the hot-deals feed.
+type DealsRequest struct {
+	Filters  DealsFilters
+	Page     int
+	PageSize int
+}
+
+// DealsResult is the payload returned for a DealsRequest.
+type DealsResult struct {
+	Products []*Product   `json:"products"`
+	Page     PageInfo     `json:"page"`
+	Filters  DealsFilters `json:"filters"`
+	// FXDegraded is true when no USD->ETB rate was available and ETB prices are unset.
+	FXDegraded bool `json:"fxDegraded"`
+}
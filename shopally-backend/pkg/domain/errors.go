@@ -0,0 +1,26 @@
+package domain
+
+import "errors"
+
+// ErrCacheMiss is returned by a CacheGateway when the key is not present.
+var ErrCacheMiss = errors.New("cache miss")
+
+// ErrPolicyBlocked is returned when a query matches a blocked content-policy term.
+var ErrPolicyBlocked = errors.New("query contains potentially harmful or prohibited content")
+
+// ErrProductNotFound is returned when the product source has no product with the requested ID.
+var ErrProductNotFound = errors.New("product not found")
+
+// ErrUpstreamUnavailable is returned when the product source is failing or
+// temporarily not called to let it recover.
+var ErrUpstreamUnavailable = errors.New("product source is temporarily unavailable")
+
+// Errors returned by the product source when retrying the same call would
+// not help. Transient failures match ErrUpstreamUnavailable instead; a
+// rate-limited call matches both it and ErrUpstreamRateLimited.
+var (
+	ErrUpstreamRateLimited    = errors.New("product source quota exceeded")
+	ErrUpstreamAuth           = errors.New("product source rejected the service credentials")
+	ErrUpstreamInvalidRequest = errors.New("product source rejected the request")
+	ErrUpstreamBadResponse    = errors.New("product source returned an unreadable response")
+)
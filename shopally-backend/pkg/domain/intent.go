@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Bounds applied when normalizing a SearchIntent.
+const (
+	DefaultShipToCountry  = "ET"
+	MaxIntentDeliveryDays = 90
+)
+
+var (
+	categoryIDsRe = regexp.MustCompile(`^\d+(,\d+)*$`)
+	countryCodeRe = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// SearchIntent is the structured form of a shopping query, produced by the
+// LLM and overlaid with explicit client filters before it reaches the
+// product gateway.
+type SearchIntent struct {
+	Keywords string `json:"keywords"`
+	// MinPrice and MaxPrice are expressed in Currency ("USD" or "ETB").
+	MinPrice   *float64 `json:"minPrice,omitempty"`
+	MaxPrice   *float64 `json:"maxPrice,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+	CategoryID string   `json:"categoryId,omitempty"`
+	// CategoryName is a category named in the query, in English or Amharic,
+	// resolved to CategoryID against the synced category tree.
+	CategoryName    string `json:"categoryName,omitempty"`
+	MaxDeliveryDays *int   `json:"maxDeliveryDays,omitempty"`
+	// Sort is a client sort option; gateways translate it to their own parameter.
+	Sort          string `json:"sort,omitempty"`
+	ShipToCountry string `json:"shipToCountry,omitempty"`
+}
+
+// IntentFieldError reports a SearchIntent field that was corrected or dropped.
+type IntentFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e IntentFieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Normalize clamps impossible values in place and reports each change. It
+// never fails: invalid optional fields are dropped and required ones reset to
+// their defaults.
+func (i *SearchIntent) Normalize() []IntentFieldError {
+	var errs []IntentFieldError
+	report := func(field, format string, args ...interface{}) {
+		errs = append(errs, IntentFieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	i.Keywords = strings.Join(strings.Fields(i.Keywords), " ")
+	if i.Keywords == "" {
+		report("keywords", "empty")
+	}
+
+	if i.MinPrice != nil && *i.MinPrice <= 0 {
+		report("minPrice", "must be positive, got %v; dropped", *i.MinPrice)
+		i.MinPrice = nil
+	}
+	if i.MaxPrice != nil && *i.MaxPrice <= 0 {
+		report("maxPrice", "must be positive, got %v; dropped", *i.MaxPrice)
+		i.MaxPrice = nil
+	}
+	if i.MinPrice != nil && i.MaxPrice != nil && *i.MinPrice > *i.MaxPrice {
+		report("minPrice", "%v is above maxPrice %v; dropped", *i.MinPrice, *i.MaxPrice)
+		i.MinPrice = nil
+	}
+
+	i.Currency = strings.ToUpper(strings.TrimSpace(i.Currency))
+	switch i.Currency {
+	case "USD", "ETB":
+	case "":
+		if i.MinPrice != nil || i.MaxPrice != nil {
+			report("currency", "missing for a price range; assuming ETB")
+			i.Currency = "ETB"
+		}
+	default:
+		report("currency", "unsupported currency %q; assuming ETB", i.Currency)
+		i.Currency = "ETB"
+	}
+
+	i.CategoryName = strings.Join(strings.Fields(i.CategoryName), " ")
+	i.CategoryID = strings.ReplaceAll(strings.TrimSpace(i.CategoryID), " ", "")
+	if i.CategoryID != "" && !categoryIDsRe.MatchString(i.CategoryID) {
+		report("categoryId", "must be comma-separated numeric IDs, got %q; dropped", i.CategoryID)
+		i.CategoryID = ""
+	}
+
+	if d := i.MaxDeliveryDays; d != nil {
+		switch {
+		case *d <= 0:
+			report("maxDeliveryDays", "must be positive, got %d; dropped", *d)
+			i.MaxDeliveryDays = nil
+		case *d > MaxIntentDeliveryDays:
+			report("maxDeliveryDays", "%d exceeds %d; clamped", *d, MaxIntentDeliveryDays)
+			clamped := MaxIntentDeliveryDays
+			i.MaxDeliveryDays = &clamped
+		}
+	}
+
+	i.ShipToCountry = strings.ToUpper(strings.TrimSpace(i.ShipToCountry))
+	if i.ShipToCountry == "" {
+		i.ShipToCountry = DefaultShipToCountry
+	} else if !countryCodeRe.MatchString(i.ShipToCountry) {
+		report("shipToCountry", "must be an ISO 3166 alpha-2 code, got %q; using %s", i.ShipToCountry, DefaultShipToCountry)
+		i.ShipToCountry = DefaultShipToCountry
+	}
+
+	return errs
+}
+
+// IntentDelta is a follow-up refinement of a SearchIntent. Set fields in Set
+// replace the previous values; Clear lists fields (by JSON name) to unset.
+type IntentDelta struct {
+	Set   SearchIntent `json:"set"`
+	Clear []string     `json:"clear,omitempty"`
+}
+
+// Apply returns i refined by d. When d sets a price in a different currency
+// the bound it leaves untouched is dropped rather than mixing currencies.
+func (i SearchIntent) Apply(d IntentDelta) SearchIntent {
+	out := i
+	for _, field := range d.Clear {
+		switch field {
+		case "keywords":
+			out.Keywords = ""
+		case "minPrice":
+			out.MinPrice = nil
+		case "maxPrice":
+			out.MaxPrice = nil
+		case "currency":
+			out.Currency = ""
+		case "categoryId":
+			out.CategoryID = ""
+			out.CategoryName = ""
+		case "maxDeliveryDays":
+			out.MaxDeliveryDays = nil
+		case "sort":
+			out.Sort = ""
+		}
+	}
+
+	set := d.Set
+	if set.MinPrice != nil || set.MaxPrice != nil {
+		if set.Currency != "" && set.Currency != out.Currency {
+			out.MinPrice, out.MaxPrice = nil, nil
+			out.Currency = set.Currency
+		}
+		if set.MinPrice != nil {
+			out.MinPrice = set.MinPrice
+		}
+		if set.MaxPrice != nil {
+			out.MaxPrice = set.MaxPrice
+		}
+	}
+	if set.Keywords != "" {
+		out.Keywords = set.Keywords
+	}
+	if set.CategoryName != "" {
+		// A newly named category replaces the previous one, resolved or not.
+		out.CategoryName = set.CategoryName
+		out.CategoryID = set.CategoryID
+	} else if set.CategoryID != "" {
+		out.CategoryID = set.CategoryID
+	}
+	if set.MaxDeliveryDays != nil {
+		out.MaxDeliveryDays = set.MaxDeliveryDays
+	}
+	if set.Sort != "" {
+		out.Sort = set.Sort
+	}
+	if set.ShipToCountry != "" {
+		out.ShipToCountry = set.ShipToCountry
+	}
+	return out
+}
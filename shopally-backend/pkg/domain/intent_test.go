@@ -0,0 +1,90 @@
+package domain
+
+import "testing"
+
+func TestSearchIntent_Normalize(t *testing.T) {
+	minPrice, maxPrice, days := 500.0, 100.0, 400
+	intent := SearchIntent{
+		Keywords:        "  red   shoes ",
+		MinPrice:        &minPrice,
+		MaxPrice:        &maxPrice,
+		Currency:        "eur",
+		CategoryID:      "12, 34",
+		MaxDeliveryDays: &days,
+		ShipToCountry:   "Ethiopia",
+	}
+
+	errs := intent.Normalize()
+
+	if intent.Keywords != "red shoes" {
+		t.Errorf("keywords not normalized: %q", intent.Keywords)
+	}
+	if intent.MinPrice != nil || intent.MaxPrice == nil || *intent.MaxPrice != 100 {
+		t.Errorf("min above max should drop min: min=%v max=%v", intent.MinPrice, intent.MaxPrice)
+	}
+	if intent.Currency != "ETB" || intent.CategoryID != "12,34" || intent.ShipToCountry != DefaultShipToCountry {
+		t.Errorf("unexpected normalized intent: %+v", intent)
+	}
+	if intent.MaxDeliveryDays == nil || *intent.MaxDeliveryDays != MaxIntentDeliveryDays {
+		t.Errorf("delivery bound not clamped: %v", intent.MaxDeliveryDays)
+	}
+
+	fields := map[string]bool{}
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	for _, f := range []string{"minPrice", "currency", "maxDeliveryDays", "shipToCountry"} {
+		if !fields[f] {
+			t.Errorf("expected an error for %s, got %v", f, errs)
+		}
+	}
+	if fields["categoryId"] || fields["keywords"] {
+		t.Errorf("valid fields reported: %v", errs)
+	}
+}
+
+func TestSearchIntent_NormalizeDropsInvalidOptionals(t *testing.T) {
+	negative, zeroDays := -5.0, 0
+	intent := SearchIntent{Keywords: "phone", MaxPrice: &negative, CategoryID: "phones", MaxDeliveryDays: &zeroDays}
+
+	errs := intent.Normalize()
+
+	if intent.MaxPrice != nil || intent.CategoryID != "" || intent.MaxDeliveryDays != nil {
+		t.Errorf("invalid optionals should be dropped: %+v", intent)
+	}
+	if intent.Currency != "" {
+		t.Errorf("currency should stay unset without prices, got %q", intent.Currency)
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected 3 field errors, got %v", errs)
+	}
+}
+
+func TestSearchIntent_Apply(t *testing.T) {
+	minPrice, maxPrice, days := 1000.0, 5000.0, 10
+	prev := SearchIntent{Keywords: "phone", MinPrice: &minPrice, MaxPrice: &maxPrice, Currency: "ETB", MaxDeliveryDays: &days, Sort: "fastest"}
+
+	newMax := 3000.0
+	got := prev.Apply(IntentDelta{Set: SearchIntent{Keywords: "red phone", MaxPrice: &newMax}, Clear: []string{"sort"}})
+	if got.Keywords != "red phone" || *got.MaxPrice != 3000 || *got.MinPrice != 1000 || got.Currency != "ETB" {
+		t.Errorf("unexpected merge: %+v", got)
+	}
+	if got.Sort != "" || got.MaxDeliveryDays == nil {
+		t.Errorf("clear should only unset listed fields: %+v", got)
+	}
+	if *prev.MaxPrice != 5000 || prev.Sort != "fastest" {
+		t.Error("Apply must not modify the receiver")
+	}
+
+	usd := 40.0
+	got = prev.Apply(IntentDelta{Set: SearchIntent{MaxPrice: &usd, Currency: "USD"}})
+	if got.MinPrice != nil || *got.MaxPrice != 40 || got.Currency != "USD" {
+		t.Errorf("a price in a new currency should replace both bounds: %+v", got)
+	}
+
+	shoes := SearchIntent{Keywords: "shoes", CategoryID: "322", CategoryName: "shoes"}
+	got = shoes.Apply(IntentDelta{Set: SearchIntent{CategoryName: "ሰዓት"}})
+	if got.CategoryName != "ሰዓት" || got.CategoryID != "" {
+		t.Errorf("a new category name should drop the resolved ID: %+v", got)
+	}
+}
@@ -6,16 +6,33 @@ import (
 )
 
 // AlibabaGateway defines the contract for fetching products from an external source.
+// pageNo and pageSize select which page of results matching intent is returned.
 type AlibabaGateway interface {
-	FetchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*Product, error)
+	FetchProducts(ctx context.Context, intent SearchIntent, pageNo, pageSize int) (*ProductPage, error)
+	// FetchProductDetail returns the full record of one product, or
+	// ErrProductNotFound when the source does not know productID.
+	FetchProductDetail(ctx context.Context, productID string) (*Product, error)
+	// FetchHotProducts returns a page of promoted products matching filters,
+	// whose prices must be in USD.
+	FetchHotProducts(ctx context.Context, filters DealsFilters, pageNo, pageSize int) (*ProductPage, error)
+	// FetchCategories returns the full category tree, parents before children.
+	FetchCategories(ctx context.Context) ([]Category, error)
+	// GenerateAffiliateLinks converts product URLs into commission-earning
+	// affiliate links, keyed by source URL. URLs it cannot convert are omitted.
+	GenerateAffiliateLinks(ctx context.Context, sourceURLs []string) (map[string]string, error)
 }
 
 // LLMGateway defines the contract for a Large Language Model service
 // to parse user intent from a search query.
 type LLMGateway interface {
-	ParseIntent(ctx context.Context, query string) (map[string]interface{}, error)
+	ParseIntent(ctx context.Context, query string) (*SearchIntent, error)
+	// RefineIntent turns a follow-up query into a delta on the previous intent,
+	// given the IDs of results the user has already seen.
+	RefineIntent(ctx context.Context, previous SearchIntent, seenIDs []string, followUp string) (*IntentDelta, error)
 	// SummarizeProduct generates short bullet points for a product based on provided fields.
 	SummarizeProduct(context.Context, *Product, string) (*Product, error)
+	// FallbackSummary returns heuristic enhanced content without calling the model.
+	FallbackSummary(ctx context.Context, p *Product, userPrompt string) *Product
 	CompareProducts(ctx context.Context, productDetails []*Product) (map[string]interface{}, error)
 }
 
@@ -29,6 +46,17 @@ type IFXClient interface {
 	GetRate(ctx context.Context, from, to string) (float64, error)
 }
 
+// FXQuote is an exchange rate together with the time it was fetched from the provider.
+type FXQuote struct {
+	Rate      float64
+	FetchedAt time.Time
+}
+
+// IFXQuoteClient is implemented by FX clients that can report when a rate was fetched.
+type IFXQuoteClient interface {
+	GetQuote(ctx context.Context, from, to string) (FXQuote, error)
+}
+
 type ICachePort interface {
 	// Get returns the value, whether it was found, and any error.
 	Get(ctx context.Context, key string) (string, bool, error)
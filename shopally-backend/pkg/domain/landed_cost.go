@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// LandedCost is what a product costs delivered to Ethiopia: the item and
+// shipping plus customs duty, excise, surtax and VAT. ETB amounts are zero
+// when no USD->ETB rate was available.
+type LandedCost struct {
+	ItemETB         float64 `json:"itemEtb"`
+	ShippingETB     float64 `json:"shippingEtb"`
+	CustomsValueETB float64 `json:"customsValueEtb"`
+	DutyETB         float64 `json:"dutyEtb"`
+	ExciseETB       float64 `json:"exciseEtb"`
+	VATETB          float64 `json:"vatEtb"`
+	SurtaxETB       float64 `json:"surtaxEtb"`
+	TotalETB        float64 `json:"totalEtb"`
+	// TotalUSD is the landed total before currency conversion.
+	TotalUSD float64         `json:"totalUsd"`
+	Rates    LandedCostRates `json:"rates"`
+	// CategoryID is the category whose duty rule applied; empty for the default rule.
+	CategoryID  string    `json:"categoryId,omitempty"`
+	FXRate      float64   `json:"fxRate,omitempty"`
+	FXTimestamp time.Time `json:"fxTimestamp"`
+}
+
+// LandedCostRates are the fractional tax rates applied in a LandedCost.
+type LandedCostRates struct {
+	Duty   float64 `json:"duty"`
+	Excise float64 `json:"excise"`
+	VAT    float64 `json:"vat"`
+	Surtax float64 `json:"surtax"`
+}
+
+// LandedCostRequest asks for the landed cost of an item outside a search.
+type LandedCostRequest struct {
+	PriceUSD float64 `json:"priceUsd"`
+	// ShippingUSD overrides the configured default shipping charge.
+	ShippingUSD *float64 `json:"shippingUsd,omitempty"`
+	// CategoryIDs lists the item's AliExpress category IDs, most specific first.
+	CategoryIDs []string `json:"categoryIds,omitempty"`
+}
+
+// LandedCostEstimate is the payload returned for a LandedCostRequest.
+type LandedCostEstimate struct {
+	LandedCost LandedCost `json:"landedCost"`
+	// FXDegraded is true when no USD->ETB rate was available and ETB amounts are unset.
+	FXDegraded bool `json:"fxDegraded"`
+}
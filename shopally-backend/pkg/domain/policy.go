@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PolicyTerm is a term the content policy blocks, tagged with its language
+// ("en" or "am").
+type PolicyTerm struct {
+	Term string `json:"term" bson:"term"`
+	Lang string `json:"lang" bson:"lang"`
+}
+
+// BlockedAttempt records a search rejected by the content policy so abuse can
+// be reviewed per device.
+type BlockedAttempt struct {
+	DeviceID  string    `json:"deviceId" bson:"device_id"`
+	Query     string    `json:"query" bson:"query"`
+	Term      string    `json:"term" bson:"term"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at"`
+}
+
+// PolicyRepository stores managed policy terms and the audit log of blocked attempts.
+type PolicyRepository interface {
+	ListTerms(ctx context.Context) ([]PolicyTerm, error)
+	RecordBlocked(ctx context.Context, attempt BlockedAttempt) error
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PricePoint is one observed price of a product.
+type PricePoint struct {
+	ProductID string  `json:"-" bson:"product_id"`
+	USD       float64 `json:"usd" bson:"usd"`
+	// ETB is zero when no exchange rate was available.
+	ETB      float64   `json:"etb,omitempty" bson:"etb,omitempty"`
+	Discount float64   `json:"discount,omitempty" bson:"discount,omitempty"`
+	At       time.Time `json:"at" bson:"at"`
+}
+
+// DailyPrice summarizes the price points of one UTC day. ETB figures only
+// cover points recorded with an exchange rate and are zero without any.
+type DailyPrice struct {
+	Date     string  `json:"date"` // YYYY-MM-DD
+	MinUSD   float64 `json:"minUsd"`
+	MaxUSD   float64 `json:"maxUsd"`
+	CloseUSD float64 `json:"closeUsd"`
+	MinETB   float64 `json:"minEtb,omitempty"`
+	MaxETB   float64 `json:"maxEtb,omitempty"`
+	CloseETB float64 `json:"closeEtb,omitempty"`
+	Points   int     `json:"points"`
+}
+
+// PriceHistory is the daily price series of a product over [From, To).
+type PriceHistory struct {
+	ProductID string       `json:"productId"`
+	From      time.Time    `json:"from"`
+	To        time.Time    `json:"to"`
+	Days      []DailyPrice `json:"days"`
+}
+
+// ProductRepository persists product snapshots and their price time series.
+type ProductRepository interface {
+	// SaveSnapshots upserts the latest snapshot of each product and appends
+	// the price points; points are never updated.
+	SaveSnapshots(ctx context.Context, products []*Product, points []PricePoint) error
+	// PricePoints returns the product's points in [from, to), oldest first.
+	PricePoints(ctx context.Context, productID string, from, to time.Time) ([]PricePoint, error)
+}
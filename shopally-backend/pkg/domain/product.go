@@ -11,14 +11,17 @@ type Price struct {
 
 // Product represents a product found on an e-commerce platform.
 type Product struct {
-	ID                 string   `json:"id"`
-	Title              string   `json:"title"`
-	ImageURL           string   `json:"imageUrl"`
-	AIMatchPercentage  int      `json:"aiMatchPercentage"`
-	Price              Price    `json:"price"`
-	ProductRating      float64  `json:"productRating"`
-	SellerScore        int      `json:"sellerScore"`
-	DeliveryEstimate   string   `json:"deliveryEstimate"`
+	ID                string  `json:"id"`
+	Title             string  `json:"title"`
+	ImageURL          string  `json:"imageUrl"`
+	AIMatchPercentage int     `json:"aiMatchPercentage"`
+	Price             Price   `json:"price"`
+	ProductRating     float64 `json:"productRating"`
+	SellerScore       int     `json:"sellerScore"`
+	DeliveryEstimate  string  `json:"deliveryEstimate"`
+	// DeliveryMinDays and DeliveryMaxDays are parsed from DeliveryEstimate; zero when unknown.
+	DeliveryMinDays    int      `json:"deliveryMinDays,omitempty"`
+	DeliveryMaxDays    int      `json:"deliveryMaxDays,omitempty"`
 	Description        string   `json:"description"`
 	CustomerHighlights string   `json:"customerHighlights"`
 	CustomerReview     string   `json:"customerReview"`
@@ -27,6 +30,66 @@ type Product struct {
 	DeeplinkURL        string   `json:"deeplinkUrl"`
 	TaxRate            float64  `json:"taxRate"`
 	Discount           float64  `json:"discount"`
+	CategoryName       string   `json:"categoryName,omitempty"`
+	// The fields below are omitted from responses when the source did not
+	// provide them, so older clients see the same payload as before.
+	// Categories is the product's category path, top level first, rather
+	// than a single category: AliExpress files products under two levels and
+	// duty rules and filters match either. CategoryName is the deepest name.
+	OriginalPrice *Price            `json:"originalPrice,omitempty"`
+	Images        []string          `json:"images,omitempty"`
+	VideoURL      string            `json:"videoUrl,omitempty"`
+	Shop          *Shop             `json:"shop,omitempty"`
+	Categories    []ProductCategory `json:"categories,omitempty"`
+	SKU           *ProductSKU       `json:"sku,omitempty"`
+	// PromotionLink and CommissionRate are the affiliate link and commission
+	// percentage returned with the product. They are never sent to clients,
+	// who follow the tracked DeeplinkURL instead.
+	PromotionLink  string  `json:"-"`
+	CommissionRate float64 `json:"-"`
+	// RecentViews counts views of the product in the "popular now" window.
+	RecentViews int `json:"recentViews,omitempty"`
+	// LandedCost is the delivered price in Ethiopia, taxes included.
+	LandedCost *LandedCost `json:"landedCost,omitempty"`
+	// AIEnriched is true when the text fields were written by the LLM rather
+	// than the heuristic fallback.
+	AIEnriched bool `json:"aiEnriched"`
+	// Score explains the product's position under the selected ranking profile.
+	Score *ScoreBreakdown `json:"score,omitempty"`
+}
+
+// Shop is the store selling a product.
+type Shop struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ProductCategory is one level of a product's category path; Level 1 is the top level.
+type ProductCategory struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Level int    `json:"level"`
+}
+
+// ProductSKU is the variant of a product the listed price refers to.
+type ProductSKU struct {
+	ID string `json:"id"`
+}
+
+// ScoreBreakdown is the weighted score a ranking profile assigned to a product.
+// Components holds each contributing signal normalized to 0..100.
+type ScoreBreakdown struct {
+	Profile    string             `json:"profile"`
+	Total      float64            `json:"total"`
+	Components map[string]float64 `json:"components"`
+}
+
+// ProductDetail is the payload returned for a single product.
+type ProductDetail struct {
+	Product *Product `json:"product"`
+	// FXDegraded is true when no USD->ETB rate was available and ETB prices are unset.
+	FXDegraded bool `json:"fxDegraded"`
 }
 
 // Synthesis captures comparison insights for a product.
@@ -0,0 +1,66 @@
+package domain
+
+// SearchFilters are search constraints. As request input they are explicit
+// client overrides, whose prices are in Currency or else USD; in a
+// SearchResult they echo the effective constraints after merging with the
+// LLM-parsed intent. Unset fields are omitted.
+type SearchFilters struct {
+	MinPrice        *float64 `json:"minPrice,omitempty"`
+	MaxPrice        *float64 `json:"maxPrice,omitempty"`
+	Currency        string   `json:"currency,omitempty"`
+	MaxDeliveryDays *int     `json:"maxDeliveryDays,omitempty"`
+	CategoryID      string   `json:"categoryId,omitempty"`
+	Sort            string   `json:"sort,omitempty"`
+}
+
+// SearchRequest carries the client-supplied inputs of a search.
+type SearchRequest struct {
+	Query    string
+	Page     int
+	PageSize int
+	Filters  SearchFilters
+	// SessionID, when set, refines the session's previous search instead of
+	// starting a new one.
+	SessionID string
+}
+
+// PageInfo describes where a page of results sits in the upstream result set.
+type PageInfo struct {
+	TotalRecordCount int    `json:"totalRecordCount"`
+	CurrentPageNo    int    `json:"currentPageNo"`
+	PageSize         int    `json:"pageSize"`
+	NextCursor       string `json:"nextCursor,omitempty"`
+}
+
+// ProductPage is a single page of products returned by an AlibabaGateway.
+type ProductPage struct {
+	Products         []*Product
+	TotalRecordCount int
+	CurrentPageNo    int
+	PageSize         int
+}
+
+// SearchResult is the payload returned by the search pipeline.
+type SearchResult struct {
+	Products []*Product    `json:"products"`
+	Page     PageInfo      `json:"page"`
+	Filters  SearchFilters `json:"filters"`
+	// FXDegraded is true when no USD->ETB rate was available and ETB prices are unset.
+	FXDegraded bool `json:"fxDegraded"`
+	// Stale is true when the product source was unavailable and a previously
+	// cached result for the same search is served instead.
+	Stale bool `json:"stale,omitempty"`
+	// BudgetFXRate is the USD->ETB rate used to convert an ETB budget to USD, if any.
+	BudgetFXRate float64 `json:"budgetFxRate,omitempty"`
+	// RankingProfile names the local ranking applied, empty when upstream order was kept.
+	RankingProfile string `json:"rankingProfile,omitempty"`
+	// FilteredOut counts fetched results dropped for violating the price or
+	// delivery constraints or the content policy.
+	FilteredOut int `json:"filteredOut,omitempty"`
+	// IntentWarnings lists intent fields that were corrected or dropped during validation.
+	IntentWarnings []IntentFieldError `json:"intentWarnings,omitempty"`
+	// Intent is the resolved intent, with prices in the client's currency.
+	Intent *SearchIntent `json:"intent,omitempty"`
+	// SessionID identifies the conversational session to pass with follow-up queries.
+	SessionID string `json:"sessionId,omitempty"`
+}
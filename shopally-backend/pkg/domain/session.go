@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// SearchSession is the conversational state behind follow-up searches: the
+// last resolved intent and the products already shown.
+type SearchSession struct {
+	ID        string       `json:"id"`
+	Intent    SearchIntent `json:"intent"`
+	SeenIDs   []string     `json:"seenIds"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RecentView is a product a device viewed, most recent view only.
+type RecentView struct {
+	ProductID string    `json:"productId"`
+	ViewedAt  time.Time `json:"viewedAt"`
+	// Product is the viewed product, when it could still be loaded.
+	Product *Product `json:"product,omitempty"`
+}
+
+// ViewRepository stores product views per device and aggregated per product.
+type ViewRepository interface {
+	RecordView(ctx context.Context, deviceID, productID string, at time.Time) error
+	// RecentViews returns up to limit of the device's viewed products, newest first.
+	RecentViews(ctx context.Context, deviceID string, limit int) ([]RecentView, error)
+	// ViewCounts returns the number of views since the given time for each
+	// product in productIDs that has any.
+	ViewCounts(ctx context.Context, productIDs []string, since time.Time) (map[string]int, error)
+}
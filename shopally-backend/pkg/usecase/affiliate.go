@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// DefaultAffiliateLinkCacheTTL is how long a generated affiliate link is reused.
+const DefaultAffiliateLinkCacheTTL = 7 * 24 * time.Hour
+
+// ErrInvalidClickToken is returned for a redirect token that is malformed or
+// was not signed by this server.
+var ErrInvalidClickToken = errors.New("invalid or tampered link token")
+
+// clickToken is the signed payload of a tracked redirect link. Tokens do not
+// name a device so that links in shared cached results stay valid for every
+// caller.
+type clickToken struct {
+	ProductID      string  `json:"p"`
+	Surface        string  `json:"s"`
+	TargetURL      string  `json:"u"`
+	CommissionRate float64 `json:"c,omitempty"`
+}
+
+// affiliateLink is the cached affiliate link of a product URL.
+type affiliateLink struct {
+	URL            string  `json:"url"`
+	CommissionRate float64 `json:"commissionRate,omitempty"`
+}
+
+// AffiliateLinker replaces product deeplinks with signed redirect links to
+// their affiliate (commission-earning) URLs. Affiliate links are generated in
+// one batch per call and cached by source URL.
+type AffiliateLinker struct {
+	alibabaGateway domain.AlibabaGateway
+	cacheGateway   domain.CacheGateway
+	secret         []byte
+
+	// BaseURL is the public origin redirect links are built on; empty yields
+	// relative "/r/<token>" links.
+	BaseURL string
+	// CacheTTL controls the affiliate link cache; it is ignored when no cache
+	// gateway is configured.
+	CacheTTL time.Duration
+}
+
+// NewAffiliateLinker creates a new AffiliateLinker signing tokens with secret.
+// cg may be nil.
+func NewAffiliateLinker(ag domain.AlibabaGateway, cg domain.CacheGateway, secret string) *AffiliateLinker {
+	return &AffiliateLinker{
+		alibabaGateway: ag,
+		cacheGateway:   cg,
+		secret:         []byte(secret),
+		CacheTTL:       DefaultAffiliateLinkCacheTTL,
+	}
+}
+
+// Apply rewrites the DeeplinkURL of every product to a tracked redirect for
+// surface. AliExpress URLs are converted to affiliate links first; links
+// already issued by this linker are re-issued for surface; other URLs are
+// left unchanged. When no affiliate link is available the redirect points at
+// the original URL so clicks are still recorded. Clients append their device
+// ID to a redirect before opening it; see the click handler.
+func (l *AffiliateLinker) Apply(ctx context.Context, products []*domain.Product, surface string) {
+	links := make(map[string]affiliateLink)
+	var missing []string
+	for _, p := range products {
+		if p == nil || !isAliExpressURL(p.DeeplinkURL) {
+			continue
+		}
+		src := p.DeeplinkURL
+		if _, ok := links[src]; ok {
+			continue
+		}
+		if p.PromotionLink != "" {
+			links[src] = affiliateLink{URL: p.PromotionLink, CommissionRate: p.CommissionRate}
+			l.writeCache(ctx, src, links[src])
+		} else if link, ok := l.readCache(ctx, src); ok {
+			links[src] = link
+		} else {
+			links[src] = affiliateLink{}
+			missing = append(missing, src)
+		}
+	}
+
+	if len(missing) > 0 {
+		generated, err := l.alibabaGateway.GenerateAffiliateLinks(ctx, missing)
+		if err != nil {
+			log.Println("AffiliateLinker: link generation failed for", len(missing), "URLs, error:", err)
+		}
+		for _, src := range missing {
+			if link, ok := generated[src]; ok {
+				links[src] = affiliateLink{URL: link}
+				l.writeCache(ctx, src, links[src])
+			}
+		}
+	}
+
+	for _, p := range products {
+		if p == nil {
+			continue
+		}
+		if tok, ok := l.parseLink(p.DeeplinkURL); ok {
+			tok.Surface = surface
+			p.DeeplinkURL = l.linkURL(tok)
+			continue
+		}
+		link, ok := links[p.DeeplinkURL]
+		if !ok {
+			continue
+		}
+		target := link.URL
+		if target == "" {
+			target = p.DeeplinkURL
+		}
+		rate := link.CommissionRate
+		if rate == 0 {
+			rate = p.CommissionRate
+		}
+		p.DeeplinkURL = l.linkURL(clickToken{ProductID: p.ID, Surface: surface, TargetURL: target, CommissionRate: rate})
+	}
+}
+
+// linkURL signs tok and returns its redirect URL.
+func (l *AffiliateLinker) linkURL(tok clickToken) string {
+	payload, _ := json.Marshal(tok)
+	enc := base64.RawURLEncoding.EncodeToString(payload)
+	return strings.TrimRight(l.BaseURL, "/") + "/r/" + enc + "." + l.sign(enc)
+}
+
+// parseToken verifies a token's signature and decodes it. Only AliExpress
+// targets are accepted, so a leaked secret cannot turn /r/ into an open
+// redirect.
+func (l *AffiliateLinker) parseToken(token string) (clickToken, error) {
+	var tok clickToken
+	enc, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(l.sign(enc))) {
+		return tok, ErrInvalidClickToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil || json.Unmarshal(payload, &tok) != nil || !isAliExpressURL(tok.TargetURL) {
+		return clickToken{}, ErrInvalidClickToken
+	}
+	return tok, nil
+}
+
+// parseLink decodes a redirect URL issued by this linker.
+func (l *AffiliateLinker) parseLink(raw string) (clickToken, bool) {
+	i := strings.LastIndex(raw, "/r/")
+	if i < 0 {
+		return clickToken{}, false
+	}
+	tok, err := l.parseToken(raw[i+len("/r/"):])
+	return tok, err == nil
+}
+
+func (l *AffiliateLinker) sign(enc string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(enc))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func (l *AffiliateLinker) readCache(ctx context.Context, src string) (affiliateLink, bool) {
+	if l.cacheGateway == nil {
+		return affiliateLink{}, false
+	}
+	key := affiliateCacheKey(src)
+	raw, err := l.cacheGateway.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			log.Println("AffiliateLinker: cache read failed for key:", key, "error:", err)
+		}
+		return affiliateLink{}, false
+	}
+	var link affiliateLink
+	if err := json.Unmarshal([]byte(raw), &link); err != nil || link.URL == "" {
+		log.Println("AffiliateLinker: discarding unreadable cache entry for key:", key)
+		return affiliateLink{}, false
+	}
+	return link, true
+}
+
+func (l *AffiliateLinker) writeCache(ctx context.Context, src string, link affiliateLink) {
+	if l.cacheGateway == nil {
+		return
+	}
+	key := affiliateCacheKey(src)
+	if err := l.cacheGateway.Set(ctx, key, link, l.CacheTTL); err != nil {
+		log.Println("AffiliateLinker: cache write failed for key:", key, "error:", err)
+	}
+}
+
+func affiliateCacheKey(src string) string {
+	sum := sha256.Sum256([]byte(src))
+	return "affiliate:" + hex.EncodeToString(sum[:])
+}
+
+// isAliExpressURL reports whether raw is an http(s) URL on an AliExpress host.
+func isAliExpressURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "aliexpress.com" || strings.HasSuffix(host, ".aliexpress.com")
+}
+
+// TrackClickUseCase records a click on a tracked link and resolves where it
+// redirects to.
+type TrackClickUseCase struct {
+	repo   domain.ClickRepository
+	linker *AffiliateLinker
+}
+
+// NewTrackClickUseCase creates a new TrackClickUseCase. repo may be nil to
+// redirect without recording clicks.
+func NewTrackClickUseCase(repo domain.ClickRepository, linker *AffiliateLinker) *TrackClickUseCase {
+	return &TrackClickUseCase{repo: repo, linker: linker}
+}
+
+// Execute verifies token, records the click for deviceID and returns the
+// target URL. A failure to record the click is logged and does not prevent
+// the redirect.
+func (uc *TrackClickUseCase) Execute(ctx context.Context, token, deviceID, userAgent string) (string, error) {
+	tok, err := uc.linker.parseToken(token)
+	if err != nil {
+		return "", err
+	}
+	if uc.repo != nil {
+		click := domain.Click{
+			ID:             uuid.NewString(),
+			DeviceID:       deviceID,
+			ProductID:      tok.ProductID,
+			Surface:        tok.Surface,
+			TargetURL:      tok.TargetURL,
+			CommissionRate: tok.CommissionRate,
+			UserAgent:      userAgent,
+			ClickedAt:      time.Now().UTC(),
+		}
+		if err := uc.repo.RecordClick(ctx, click); err != nil {
+			log.Println("TrackClickUseCase: failed to record click on product:", tok.ProductID, "error:", err)
+		}
+	}
+	return tok.TargetURL, nil
+}
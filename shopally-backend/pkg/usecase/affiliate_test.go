@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func linkFixture() []*domain.Product {
+	return []*domain.Product{
+		{ID: "1", DeeplinkURL: "https://www.aliexpress.com/item/1.html"},
+		{ID: "2", DeeplinkURL: "https://www.aliexpress.com/item/2.html", PromotionLink: "https://s.click.aliexpress.com/e/_two", CommissionRate: 7},
+		{ID: "3", DeeplinkURL: "https://www.aliexpress.com/item/3.html"},
+		{ID: "4", DeeplinkURL: "#"},
+	}
+}
+
+func TestAffiliateLinker_BatchesAndCachesLinks(t *testing.T) {
+	ag := &fakeAlibabaGateway{}
+	linker := NewAffiliateLinker(ag, newMemCacheGateway(), "secret")
+	linker.BaseURL = "https://api.example.com/"
+
+	products := linkFixture()
+	linker.Apply(context.Background(), products, domain.ClickSurfaceSearch)
+
+	if got := atomic.LoadInt32(&ag.linkCalls); got != 1 || len(ag.linkSources) != 2 {
+		t.Fatalf("expected one batch for the two unlinked URLs, got %d calls for %v", got, ag.linkSources)
+	}
+	for _, p := range products[:3] {
+		if !strings.HasPrefix(p.DeeplinkURL, "https://api.example.com/r/") {
+			t.Fatalf("expected tracked link for %s, got %s", p.ID, p.DeeplinkURL)
+		}
+	}
+	if products[3].DeeplinkURL != "#" {
+		t.Errorf("expected non-AliExpress links untouched, got %s", products[3].DeeplinkURL)
+	}
+
+	tok, ok := linker.parseLink(products[1].DeeplinkURL)
+	if !ok || tok.TargetURL != "https://s.click.aliexpress.com/e/_two" || tok.CommissionRate != 7 || tok.Surface != domain.ClickSurfaceSearch {
+		t.Errorf("expected promotion link reused, got %+v", tok)
+	}
+	tok, _ = linker.parseLink(products[0].DeeplinkURL)
+	if tok.TargetURL != "https://www.aliexpress.com/item/1.html?aff=1" || tok.ProductID != "1" {
+		t.Errorf("expected generated link, got %+v", tok)
+	}
+
+	linker.Apply(context.Background(), linkFixture(), domain.ClickSurfaceDeals)
+	if got := atomic.LoadInt32(&ag.linkCalls); got != 1 {
+		t.Errorf("expected cached links on the second call, got %d calls", got)
+	}
+}
+
+func TestAffiliateLinker_FallsBackAndReissues(t *testing.T) {
+	ag := &fakeAlibabaGateway{linkErr: errors.New("upstream down")}
+	linker := NewAffiliateLinker(ag, nil, "secret")
+
+	products := linkFixture()[:1]
+	linker.Apply(context.Background(), products, domain.ClickSurfaceSearch)
+	tok, ok := linker.parseLink(products[0].DeeplinkURL)
+	if !ok || tok.TargetURL != "https://www.aliexpress.com/item/1.html" {
+		t.Fatalf("expected a tracked link to the original URL, got %s (%+v)", products[0].DeeplinkURL, tok)
+	}
+
+	// A link shown in search and sent back to compare is re-issued for compare.
+	linker.Apply(context.Background(), products, domain.ClickSurfaceCompare)
+	tok, ok = linker.parseLink(products[0].DeeplinkURL)
+	if !ok || tok.Surface != domain.ClickSurfaceCompare || tok.TargetURL != "https://www.aliexpress.com/item/1.html" {
+		t.Errorf("expected link re-issued for compare, got %+v", tok)
+	}
+	if got := atomic.LoadInt32(&ag.linkCalls); got != 1 {
+		t.Errorf("expected tracked links not to be regenerated, got %d calls", got)
+	}
+}
+
+func TestTrackClick_RecordsAndResolves(t *testing.T) {
+	linker := NewAffiliateLinker(&fakeAlibabaGateway{}, nil, "secret")
+	p := &domain.Product{ID: "9", DeeplinkURL: "https://www.aliexpress.com/item/9.html", PromotionLink: "https://s.click.aliexpress.com/e/_nine"}
+	linker.Apply(context.Background(), []*domain.Product{p}, domain.ClickSurfaceDeals)
+	token := p.DeeplinkURL[strings.LastIndex(p.DeeplinkURL, "/r/")+3:]
+
+	repo := mocks.NewClickRepository(t)
+	repo.On("RecordClick", mock.Anything, mock.MatchedBy(func(c domain.Click) bool {
+		return c.DeviceID == "device-a" && c.ProductID == "9" && c.Surface == domain.ClickSurfaceDeals && c.ID != "" && !c.ClickedAt.IsZero()
+	})).Return(errors.New("mongo down")).Once()
+	uc := NewTrackClickUseCase(repo, linker)
+
+	target, err := uc.Execute(context.Background(), token, "device-a", "test-agent")
+	if err != nil || target != "https://s.click.aliexpress.com/e/_nine" {
+		t.Fatalf("expected redirect despite storage failure, got %q (%v)", target, err)
+	}
+
+	for _, bad := range []string{"", "abc", token + "x", "e30." + token[strings.Index(token, ".")+1:]} {
+		if _, err := uc.Execute(context.Background(), bad, "", ""); !errors.Is(err, ErrInvalidClickToken) {
+			t.Errorf("expected ErrInvalidClickToken for %q, got %v", bad, err)
+		}
+	}
+	// Correctly signed tokens may still only redirect to AliExpress.
+	for _, target := range []string{"https://evil.example/phish", "https://aliexpress.com.evil.example/", "javascript:alert(1)"} {
+		forged := linker.linkURL(clickToken{ProductID: "9", TargetURL: target})
+		if _, err := uc.Execute(context.Background(), forged[strings.LastIndex(forged, "/r/")+3:], "", ""); !errors.Is(err, ErrInvalidClickToken) {
+			t.Errorf("expected ErrInvalidClickToken for target %q, got %v", target, err)
+		}
+	}
+	other := NewTrackClickUseCase(nil, NewAffiliateLinker(nil, nil, "other"))
+	if _, err := other.Execute(context.Background(), token, "", ""); !errors.Is(err, ErrInvalidClickToken) {
+		t.Errorf("expected tokens signed with another secret to be rejected, got %v", err)
+	}
+}
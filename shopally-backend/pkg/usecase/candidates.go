@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+const (
+	// DefaultCandidateTarget is how many upstream products a search ranks
+	// before returning its page.
+	DefaultCandidateTarget = 100
+	// DefaultFetchConcurrency caps simultaneous upstream page fetches per search.
+	DefaultFetchConcurrency = 3
+	// DefaultFetchInterval is the minimum time between two extra upstream page
+	// fetches across all searches. The first page of a search is not delayed.
+	DefaultFetchInterval = 100 * time.Millisecond
+
+	// maxCandidatePages caps the upstream pages, of MaxPageSize products each,
+	// behind a search; results past them are not reachable.
+	maxCandidatePages = 10
+	// maxPoolResults is the most results a pool holds, and so the furthest
+	// any page can reach.
+	maxPoolResults = maxCandidatePages * MaxPageSize
+)
+
+// candidatePages returns how many upstream pages hold the candidate pool of
+// CandidateTarget products. It does not depend on the requested page, so
+// every page of a query is cut from the same ranking.
+func (uc *SearchProductsUseCase) candidatePages() int {
+	return min((max(uc.CandidateTarget, 1)+MaxPageSize-1)/MaxPageSize, maxCandidatePages)
+}
+
+// fetchPages fetches upstream pages from..to of MaxPageSize products, at most
+// FetchConcurrency at a time and no faster than FetchInterval allows. The
+// pages are returned in order; a page that failed is nil.
+func (uc *SearchProductsUseCase) fetchPages(ctx context.Context, intent domain.SearchIntent, from, to int) []*domain.ProductPage {
+	if to < from {
+		return nil
+	}
+	pages := make([]*domain.ProductPage, to-from+1)
+	sem := make(chan struct{}, max(uc.FetchConcurrency, 1))
+	var wg sync.WaitGroup
+	for pageNo := from; pageNo <= to; pageNo++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil || uc.fetchLimiter.wait(ctx, uc.FetchInterval) != nil {
+			break
+		}
+		wg.Add(1)
+		go func(pageNo int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			page, err := uc.alibabaGateway.FetchProducts(ctx, intent, pageNo, MaxPageSize)
+			if err != nil {
+				log.Println("SearchProductsUseCase: fetching page", pageNo, "failed, error:", err)
+				return
+			}
+			pages[pageNo-from] = page
+		}(pageNo)
+	}
+	wg.Wait()
+	return pages
+}
+
+// pageLimiter spaces calls at least an interval apart.
+type pageLimiter struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the caller's slot, or returns ctx.Err().
+func (l *pageLimiter) wait(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	at := time.Now()
+	if l.next.After(at) {
+		at = l.next
+	}
+	l.next = at.Add(interval)
+	l.mu.Unlock()
+
+	d := time.Until(at)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
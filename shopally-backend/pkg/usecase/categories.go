@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// DefaultCategoryAliases maps AliExpress category names to the Amharic terms
+// shoppers use for them. Curated aliases stored on a category are added to these.
+var DefaultCategoryAliases = map[string][]string{
+	"Phones & Telecommunications": {"ስልክ", "ሞባይል"},
+	"Computer & Office":           {"ኮምፒዩተር", "ኮምፒውተር", "ላፕቶፕ"},
+	"Consumer Electronics":        {"ኤሌክትሮኒክስ"},
+	"Home Appliances":             {"የቤት እቃዎች", "የቤት መገልገያ"},
+	"Watches":                     {"ሰዓት"},
+	"Shoes":                       {"ጫማ"},
+	"Women's Clothing":            {"የሴቶች ልብስ"},
+	"Men's Clothing":              {"የወንዶች ልብስ"},
+	"Beauty & Health":             {"ውበት", "የውበት እቃዎች"},
+	"Toys & Hobbies":              {"መጫወቻ", "አሻንጉሊት"},
+	"Jewelry & Accessories":       {"ጌጣጌጥ"},
+	"Luggage & Bags":              {"ቦርሳ", "ሻንጣ"},
+}
+
+// CategoryCatalog is an in-memory copy of the synced category tree used to
+// browse categories and to resolve category names in search intents.
+type CategoryCatalog struct {
+	repo    domain.CategoryRepository
+	aliases map[string][]string // normalized category name -> terms
+
+	mu         sync.RWMutex
+	categories []domain.Category
+	byID       map[string]domain.Category
+	terms      map[string][]string // category ID -> normalized terms
+}
+
+// NewCategoryCatalog builds an empty catalog; call Refresh to load it. aliases
+// maps category names to extra terms and is added to DefaultCategoryAliases.
+func NewCategoryCatalog(repo domain.CategoryRepository, aliases map[string][]string) *CategoryCatalog {
+	merged := make(map[string][]string)
+	for _, src := range []map[string][]string{DefaultCategoryAliases, aliases} {
+		for name, terms := range src {
+			key := normalizeQuery(name)
+			merged[key] = append(merged[key], terms...)
+		}
+	}
+	return &CategoryCatalog{repo: repo, aliases: merged, byID: map[string]domain.Category{}}
+}
+
+// Refresh reloads the tree from the repository. On error the previous tree
+// stays in effect.
+func (c *CategoryCatalog) Refresh(ctx context.Context) error {
+	if c.repo == nil {
+		return nil
+	}
+	categories, err := c.repo.ListCategories(ctx)
+	if err != nil {
+		return err
+	}
+	c.Load(categories)
+	return nil
+}
+
+// Load replaces the catalog contents.
+func (c *CategoryCatalog) Load(categories []domain.Category) {
+	sorted := append([]domain.Category(nil), categories...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Level != sorted[j].Level {
+			return sorted[i].Level < sorted[j].Level
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	byID := make(map[string]domain.Category, len(sorted))
+	terms := make(map[string][]string, len(sorted))
+	for _, cat := range sorted {
+		byID[cat.ID] = cat
+		list := append([]string{cat.Name, cat.NameAM}, cat.Aliases...)
+		list = append(list, c.aliases[normalizeQuery(cat.Name)]...)
+		terms[cat.ID] = normalizeTerms(list)
+	}
+
+	c.mu.Lock()
+	c.categories, c.byID, c.terms = sorted, byID, terms
+	c.mu.Unlock()
+}
+
+// List returns the children of parentID, or the top-level categories when
+// parentID is empty.
+func (c *CategoryCatalog) List(parentID string) []domain.Category {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := []domain.Category{}
+	for _, cat := range c.categories {
+		if cat.ParentID == parentID {
+			out = append(out, cat)
+		}
+	}
+	return out
+}
+
+// Resolve finds the category best matching name. An exact name or alias match
+// wins, then a category whose name or alias is fully mentioned in name, then
+// one whose name contains every word of name; ties go to the deeper level.
+func (c *CategoryCatalog) Resolve(name string) (domain.Category, bool) {
+	query := normalizeQuery(name)
+	if query == "" {
+		return domain.Category{}, false
+	}
+	queryWords := categoryWords(query)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var best domain.Category
+	bestRank := 0
+	for _, cat := range c.categories {
+		rank := 0
+		for _, term := range c.terms[cat.ID] {
+			switch termWords := categoryWords(term); {
+			case term == query:
+				rank = max(rank, 3)
+			case containsWords(queryWords, termWords):
+				rank = max(rank, 2)
+			case containsWords(termWords, queryWords):
+				rank = max(rank, 1)
+			}
+		}
+		if rank > bestRank || (rank == bestRank && rank > 0 && cat.Level > best.Level) {
+			best, bestRank = cat, rank
+		}
+	}
+	return best, bestRank > 0
+}
+
+// ResolveIntent sets the intent's CategoryID from its CategoryName and drops
+// category IDs the tree does not contain. It does nothing before the first
+// successful Refresh.
+func (c *CategoryCatalog) ResolveIntent(intent *domain.SearchIntent) []domain.IntentFieldError {
+	c.mu.RLock()
+	loaded := len(c.byID) > 0
+	c.mu.RUnlock()
+	if !loaded {
+		return nil
+	}
+
+	var errs []domain.IntentFieldError
+	if intent.CategoryName != "" {
+		if cat, ok := c.Resolve(intent.CategoryName); ok {
+			intent.CategoryID = cat.ID
+			return nil
+		}
+		errs = append(errs, domain.IntentFieldError{Field: "categoryName", Message: fmt.Sprintf("no category matches %q", intent.CategoryName)})
+	}
+	if intent.CategoryID == "" {
+		return errs
+	}
+
+	var known []string
+	c.mu.RLock()
+	for _, id := range strings.Split(intent.CategoryID, ",") {
+		if _, ok := c.byID[strings.TrimSpace(id)]; ok {
+			known = append(known, strings.TrimSpace(id))
+		} else {
+			errs = append(errs, domain.IntentFieldError{Field: "categoryId", Message: fmt.Sprintf("unknown category %q; dropped", id)})
+		}
+	}
+	c.mu.RUnlock()
+	intent.CategoryID = strings.Join(known, ",")
+	return errs
+}
+
+// categoryWords splits a normalized name into words, ignoring punctuation
+// such as "&" and a plural "s" so "phone" matches "Phones".
+func categoryWords(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '&' || r == ',' || r == '/' || r == '-'
+	})
+	out := fields[:0]
+	for _, w := range fields {
+		w = strings.TrimSuffix(w, "'s")
+		if len(w) > 3 && strings.HasSuffix(w, "s") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// containsWords reports whether every word of sub appears in words.
+func containsWords(words, sub []string) bool {
+	if len(sub) == 0 {
+		return false
+	}
+	for _, s := range sub {
+		found := false
+		for _, w := range words {
+			if w == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// errEmptyCategoryTree guards against wiping the tree with an empty upstream response.
+var errEmptyCategoryTree = errors.New("category source returned no categories")
+
+// SyncCategoriesUseCase copies the AliExpress category tree into the repository.
+type SyncCategoriesUseCase struct {
+	alibabaGateway domain.AlibabaGateway
+	repo           domain.CategoryRepository
+}
+
+// NewSyncCategoriesUseCase creates a new SyncCategoriesUseCase.
+func NewSyncCategoriesUseCase(ag domain.AlibabaGateway, repo domain.CategoryRepository) *SyncCategoriesUseCase {
+	return &SyncCategoriesUseCase{alibabaGateway: ag, repo: repo}
+}
+
+// Execute fetches the tree, upserts it and removes the categories AliExpress
+// no longer returns, returning the number of categories synced.
+func (uc *SyncCategoriesUseCase) Execute(ctx context.Context) (int, error) {
+	categories, err := uc.alibabaGateway.FetchCategories(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(categories) == 0 {
+		return 0, errEmptyCategoryTree
+	}
+	// Stored times keep millisecond precision; truncate so every upserted
+	// category compares equal to now when pruning.
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i := range categories {
+		categories[i].UpdatedAt = now
+	}
+	if err := uc.repo.UpsertCategories(ctx, categories); err != nil {
+		return 0, err
+	}
+	removed, err := uc.repo.DeleteCategoriesBefore(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("prune removed categories: %w", err)
+	}
+	log.Println("SyncCategoriesUseCase: synced", len(categories), "categories, removed", removed)
+	return len(categories), nil
+}
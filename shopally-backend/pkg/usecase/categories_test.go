@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func categoryFixture() []domain.Category {
+	return []domain.Category{
+		{ID: "509", Name: "Phones & Telecommunications", Level: 1},
+		{ID: "5090301", ParentID: "509", Name: "Mobile Phones", Level: 2},
+		{ID: "322", Name: "Shoes", Level: 1},
+		{ID: "44", Name: "Consumer Electronics", Level: 1},
+		{ID: "63705", ParentID: "44", Name: "Earphones & Headphones", Level: 2, Aliases: []string{"የጆሮ ማዳመጫ"}},
+	}
+}
+
+func TestCategoryCatalog_ResolvesEnglishAndAmharicNames(t *testing.T) {
+	c := NewCategoryCatalog(nil, map[string][]string{"Consumer Electronics": {"gadgets"}})
+	c.Load(categoryFixture())
+
+	cases := map[string]string{
+		"Shoes":             "322",
+		"running shoes":     "322",
+		"ጫማ":                "322",
+		"phone":             "5090301",
+		"ስልክ":               "509",
+		"headphones":        "63705",
+		"የጆሮ ማዳመጫ":          "63705",
+		"cool gadgets":      "44",
+		"telecommunication": "509",
+	}
+	for name, want := range cases {
+		got, ok := c.Resolve(name)
+		if !ok || got.ID != want {
+			t.Errorf("%q: expected %s, got %q (found=%v)", name, want, got.ID, ok)
+		}
+	}
+	if got, ok := c.Resolve("garden furniture"); ok {
+		t.Errorf("unexpected match %s", got.ID)
+	}
+}
+
+func TestCategoryCatalog_ResolveIntent(t *testing.T) {
+	c := NewCategoryCatalog(nil, nil)
+
+	untouched := domain.SearchIntent{CategoryID: "999", CategoryName: "shoes"}
+	if errs := c.ResolveIntent(&untouched); errs != nil || untouched.CategoryID != "999" {
+		t.Fatalf("an empty catalog must not change the intent, got %+v %v", untouched, errs)
+	}
+
+	c.Load(categoryFixture())
+	named := domain.SearchIntent{CategoryID: "999", CategoryName: "ጫማ"}
+	if errs := c.ResolveIntent(&named); len(errs) != 0 || named.CategoryID != "322" {
+		t.Errorf("expected the name to win, got %+v %v", named, errs)
+	}
+
+	guessed := domain.SearchIntent{CategoryID: "44,999", CategoryName: "garden"}
+	errs := c.ResolveIntent(&guessed)
+	if guessed.CategoryID != "44" || len(errs) != 2 {
+		t.Errorf("expected the unknown ID dropped with two warnings, got %+v %v", guessed, errs)
+	}
+}
+
+func TestSearch_ResolvesCategoryBeforeFetching(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{Keywords: "sneakers", CategoryName: "ጫማ"}}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+	uc.Categories = NewCategoryCatalog(nil, nil)
+	uc.Categories.Load(categoryFixture())
+
+	res, err := uc.Search(searchCtx("am"), domain.SearchRequest{Query: "ጫማ"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if ag.lastIntent.CategoryID != "322" || res.Filters.CategoryID != "322" {
+		t.Errorf("expected category 322 sent upstream, got %q (filters %q)", ag.lastIntent.CategoryID, res.Filters.CategoryID)
+	}
+}
+
+func TestSyncCategories(t *testing.T) {
+	repo := mocks.NewCategoryRepository(t)
+	var syncedAt time.Time
+	repo.On("UpsertCategories", mock.Anything, mock.MatchedBy(func(cats []domain.Category) bool {
+		syncedAt = cats[0].UpdatedAt
+		return len(cats) == 5 && !syncedAt.IsZero()
+	})).Return(nil).Once()
+	// Categories missing from this sync are pruned.
+	repo.On("DeleteCategoriesBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return before.Equal(syncedAt)
+	})).Return(int64(2), nil).Once()
+
+	n, err := NewSyncCategoriesUseCase(&fakeAlibabaGateway{categories: categoryFixture()}, repo).Execute(context.Background())
+	if err != nil || n != 5 {
+		t.Fatalf("expected 5 synced, got %d (%v)", n, err)
+	}
+
+	if _, err := NewSyncCategoriesUseCase(&fakeAlibabaGateway{}, repo).Execute(context.Background()); !errors.Is(err, errEmptyCategoryTree) {
+		t.Errorf("an empty tree must not be synced, got %v", err)
+	}
+}
@@ -14,22 +14,58 @@ type CompareProductsExecutor interface {
 // CompareProductsUseCase is the real implementation that calls the LLM gateway.
 type CompareProductsUseCase struct {
 	llmGateway domain.LLMGateway
+	fxClient   domain.IFXClient
+
+	// LandedCost, if set, attaches the Ethiopian landed cost to each product
+	// before comparison.
+	LandedCost *LandedCostCalculator
+	// Affiliate, if set, replaces deeplinks with tracked affiliate redirects.
+	Affiliate *AffiliateLinker
 }
 
 var _ CompareProductsExecutor = (*CompareProductsUseCase)(nil)
 
-// Execute delegates to the LLMGateway to compare products.
+// Execute refreshes the ETB prices of the products and delegates to the
+// LLMGateway to compare them. The result carries an "fxDegraded" flag and,
+// when landed costs are enabled, a "landedCosts" map keyed by product ID.
 func (uc *CompareProductsUseCase) Execute(ctx context.Context, products []*domain.Product) (interface{}, error) {
+	quote, fxOK := etbQuote(ctx, uc.fxClient)
+	if fxOK {
+		applyETBPrices(products, quote)
+	}
+	landed := map[string]*domain.LandedCost{}
+	if uc.LandedCost != nil {
+		uc.LandedCost.Apply(products, quote, fxOK)
+		for _, p := range products {
+			if p != nil && p.LandedCost != nil {
+				landed[p.ID] = p.LandedCost
+			}
+		}
+	}
+
+	if uc.Affiliate != nil {
+		uc.Affiliate.Apply(ctx, products, domain.ClickSurfaceCompare)
+	}
+
 	result, err := uc.llmGateway.CompareProducts(ctx, products)
 	if err != nil {
 		return nil, err
 	}
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+	result["fxDegraded"] = !fxOK
+	if uc.LandedCost != nil {
+		result["landedCosts"] = landed
+	}
 	return result, nil
 }
 
 // NewCompareProductsUseCase creates a new use case instance.
-func NewCompareProductsUseCase(lg domain.LLMGateway) *CompareProductsUseCase {
+func NewCompareProductsUseCase(lg domain.LLMGateway, fx domain.IFXClient) *CompareProductsUseCase {
 	return &CompareProductsUseCase{
 		llmGateway: lg,
+		fxClient:   fx,
+		LandedCost: NewLandedCostCalculator(DefaultLandedCostSettings()),
 	}
 }
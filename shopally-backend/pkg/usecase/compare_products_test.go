@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareProducts_ConvertsPricesBeforeComparing(t *testing.T) {
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(100.0, nil).Once()
+	uc := NewCompareProductsUseCase(&fakeLLMGateway{}, fx)
+
+	products := []*domain.Product{
+		{ID: "a", Price: domain.Price{USD: 1.5}},
+		{ID: "b", Price: domain.Price{USD: 2}},
+	}
+	out, err := uc.Execute(context.Background(), products)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 150.0, products[0].Price.ETB, 1e-9)
+	assert.InDelta(t, 200.0, products[1].Price.ETB, 1e-9)
+	assert.Equal(t, false, out.(map[string]interface{})["fxDegraded"])
+}
+
+func TestCompareProducts_AttachesLandedCosts(t *testing.T) {
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(100.0, nil).Once()
+	uc := NewCompareProductsUseCase(&fakeLLMGateway{}, fx)
+
+	products := []*domain.Product{
+		{ID: "a", Price: domain.Price{USD: 10}},
+		{ID: "b", Price: domain.Price{USD: 20}},
+	}
+	out, err := uc.Execute(context.Background(), products)
+	require.NoError(t, err)
+
+	landed := out.(map[string]interface{})["landedCosts"].(map[string]*domain.LandedCost)
+	require.Len(t, landed, 2)
+	assert.Greater(t, landed["a"].TotalETB, 1000.0)
+	assert.Same(t, products[1].LandedCost, landed["b"])
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// DefaultBackfillPages caps the extra upstream pages fetched to replace
+// results dropped by local constraint enforcement.
+const DefaultBackfillPages = 2
+
+var deliveryDaysRe = regexp.MustCompile(`\d+`)
+
+// ParseDeliveryEstimate extracts a day range from free-text estimates such as
+// "ship to RU in 7 days", "15-30 days" or "3 to 5". A single number yields
+// min == max.
+func ParseDeliveryEstimate(s string) (minDays, maxDays int, ok bool) {
+	nums := deliveryDaysRe.FindAllString(s, -1)
+	if len(nums) == 0 {
+		return 0, 0, false
+	}
+	first, err1 := strconv.Atoi(nums[0])
+	last, err2 := strconv.Atoi(nums[len(nums)-1])
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	if first > last {
+		first, last = last, first
+	}
+	return first, last, true
+}
+
+// annotateDelivery fills the structured delivery bounds from DeliveryEstimate
+// where the gateway left them unset.
+func annotateDelivery(products []*domain.Product) {
+	for _, p := range products {
+		if p.DeliveryMaxDays > 0 {
+			continue
+		}
+		if lo, hi, ok := ParseDeliveryEstimate(p.DeliveryEstimate); ok {
+			p.DeliveryMinDays, p.DeliveryMaxDays = lo, hi
+		}
+	}
+}
+
+// searchConstraints are the price (USD) and delivery bounds a result must
+// satisfy, read from the intent after budget currency normalization.
+type searchConstraints struct {
+	minUSD, maxUSD *float64
+	maxDays        *int
+}
+
+func constraintsFromIntent(intent domain.SearchIntent) searchConstraints {
+	return searchConstraints{minUSD: intent.MinPrice, maxUSD: intent.MaxPrice, maxDays: intent.MaxDeliveryDays}
+}
+
+// split partitions products into those that satisfy the constraints and
+// those whose price or delivery is unknown and so are demoted rather than
+// dropped. It returns how many products definitely violate a constraint.
+func (c searchConstraints) split(products []*domain.Product) (matching, demoted []*domain.Product, dropped int) {
+	for _, p := range products {
+		switch c.check(p) {
+		case constraintMet:
+			matching = append(matching, p)
+		case constraintUnknown:
+			demoted = append(demoted, p)
+		default:
+			dropped++
+		}
+	}
+	return matching, demoted, dropped
+}
+
+type constraintResult int
+
+const (
+	constraintMet constraintResult = iota
+	constraintUnknown
+	constraintViolated
+)
+
+func (c searchConstraints) check(p *domain.Product) constraintResult {
+	result := constraintMet
+	if c.minUSD != nil || c.maxUSD != nil {
+		switch price := p.Price.USD; {
+		case price <= 0:
+			result = constraintUnknown
+		case c.minUSD != nil && price < *c.minUSD, c.maxUSD != nil && price > *c.maxUSD:
+			return constraintViolated
+		}
+	}
+	if c.maxDays != nil {
+		switch {
+		case p.DeliveryMaxDays <= 0:
+			result = constraintUnknown
+		case p.DeliveryMinDays > *c.maxDays:
+			return constraintViolated
+		case p.DeliveryMaxDays > *c.maxDays:
+			// The range straddles the bound: it may arrive in time but is not promised to.
+			result = constraintUnknown
+		}
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+func TestParseDeliveryEstimate(t *testing.T) {
+	cases := []struct {
+		in       string
+		min, max int
+		ok       bool
+	}{
+		{"ship to RU in 7 days", 7, 7, true},
+		{"15-30 days", 15, 30, true},
+		{"3 to 5", 3, 5, true},
+		{"20-10", 10, 20, true},
+		{"", 0, 0, false},
+		{"fast shipping", 0, 0, false},
+	}
+	for _, tc := range cases {
+		lo, hi, ok := ParseDeliveryEstimate(tc.in)
+		if lo != tc.min || hi != tc.max || ok != tc.ok {
+			t.Errorf("%q: got (%d, %d, %v), want (%d, %d, %v)", tc.in, lo, hi, ok, tc.min, tc.max, tc.ok)
+		}
+	}
+}
+
+func TestSearchConstraints_Split(t *testing.T) {
+	c := constraintsFromIntent(domain.SearchIntent{MinPrice: floatPtr(10), MaxPrice: floatPtr(50), MaxDeliveryDays: intPtr(10)})
+	products := []*domain.Product{
+		{ID: "ok", Price: domain.Price{USD: 20}, DeliveryEstimate: "5-8 days"},
+		{ID: "too-cheap", Price: domain.Price{USD: 5}, DeliveryEstimate: "5 days"},
+		{ID: "too-dear", Price: domain.Price{USD: 80}, DeliveryEstimate: "5 days"},
+		{ID: "too-slow", Price: domain.Price{USD: 20}, DeliveryEstimate: "15-30 days"},
+		{ID: "straddles", Price: domain.Price{USD: 20}, DeliveryEstimate: "7-14 days"},
+		{ID: "no-eta", Price: domain.Price{USD: 20}},
+		{ID: "no-price", DeliveryEstimate: "3 days"},
+	}
+	annotateDelivery(products)
+
+	matching, demoted, dropped := c.split(products)
+	if len(matching) != 1 || matching[0].ID != "ok" {
+		t.Errorf("unexpected matches: %v", ids(matching))
+	}
+	if got := ids(demoted); len(got) != 3 || got[0] != "straddles" || got[1] != "no-eta" || got[2] != "no-price" {
+		t.Errorf("unexpected demoted: %v", got)
+	}
+	if dropped != 3 {
+		t.Errorf("expected 3 dropped, got %d", dropped)
+	}
+	if products[0].DeliveryMinDays != 5 || products[0].DeliveryMaxDays != 8 {
+		t.Errorf("delivery not annotated: %+v", products[0])
+	}
+}
+
+func TestSearch_EnforcesConstraintsAndBackfills(t *testing.T) {
+	ag := &fakeAlibabaGateway{
+		total: 3 * MaxPageSize,
+		pages: map[int][]*domain.Product{
+			1: {
+				{ID: "a", Price: domain.Price{USD: 20}, DeliveryEstimate: "5 days"},
+				{ID: "b", Price: domain.Price{USD: 200}, DeliveryEstimate: "5 days"},
+				{ID: "c", Price: domain.Price{USD: 20}},
+			},
+			2: {
+				{ID: "a", Price: domain.Price{USD: 20}, DeliveryEstimate: "5 days"},
+				{ID: "d", Price: domain.Price{USD: 30}, DeliveryEstimate: "ship to ET in 9 days"},
+				{ID: "e", Price: domain.Price{USD: 40}, DeliveryEstimate: "25-40 days"},
+			},
+			3: {
+				{ID: "f", Price: domain.Price{USD: 45}, DeliveryEstimate: "3-4 days"},
+			},
+		},
+	}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{MaxPrice: floatPtr(50), Currency: "USD", MaxDeliveryDays: intPtr(10)}}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+	uc.CandidateTarget = MaxPageSize
+	uc.FetchInterval = 0
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "watch", PageSize: 3, Filters: domain.SearchFilters{Sort: "price_desc"}})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	if got := ids(res.Products); len(got) != 3 || got[0] != "a" || got[1] != "d" || got[2] != "f" {
+		t.Errorf("expected matches from backfilled pages, got %v", got)
+	}
+	if ag.calls != 3 {
+		t.Errorf("expected 3 upstream fetches, got %d", ag.calls)
+	}
+	if res.FilteredOut != 2 {
+		t.Errorf("expected 2 filtered out, got %d", res.FilteredOut)
+	}
+	if res.Page.CurrentPageNo != 1 {
+		t.Errorf("current page should stay the requested one, got %d", res.Page.CurrentPageNo)
+	}
+	// The demoted product is left for the next page of the pool.
+	if page, _, err := DecodePageCursor(res.Page.NextCursor); err != nil || page != 2 {
+		t.Errorf("next cursor should point at the second page of the pool, got page %d (%v)", page, err)
+	}
+}
+
+func TestSearch_DemotesUnknownDelivery(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{
+		{ID: "unknown", Price: domain.Price{USD: 20}, ProductRating: 100},
+		{ID: "known", Price: domain.Price{USD: 20}, DeliveryEstimate: "4 days"},
+	}}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{MaxDeliveryDays: intPtr(7)}}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "watch"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := ids(res.Products); len(got) != 2 || got[0] != "known" || got[1] != "unknown" {
+		t.Errorf("unknown delivery should be demoted, got %v", got)
+	}
+	if res.Products[0].DeliveryMaxDays != 4 {
+		t.Errorf("structured delivery days missing: %+v", res.Products[0])
+	}
+}
+
+func ids(products []*domain.Product) []string {
+	out := make([]string, 0, len(products))
+	for _, p := range products {
+		out = append(out, p.ID)
+	}
+	return out
+}
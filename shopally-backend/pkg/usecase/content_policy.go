@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// DefaultPolicyTerms are the built-in blocked terms, used for a language
+// when no list is configured for it.
+var DefaultPolicyTerms = map[string][]string{
+	"en": {
+		"drugs", "weapons", "firearms", "explosives", "contraband",
+		"porn", "sex toys", "adult content", "erotic", "hentai",
+		"illegal", "smuggled", "stolen goods", "counterfeit",
+		"hate speech", "violence", "racist", "discriminatory",
+	},
+	"am": {
+		"አደንዛዥ ዕፅ", "የጦር መሳሪያ", "ጠመንጃ", "ፈንጂ", "ኮንትሮባንድ",
+		"የብልግና", "ወሲብ", "የተሰረቀ", "ሕገ ወጥ",
+	},
+}
+
+const policyAuditTimeout = 5 * time.Second
+
+// ContentPolicy blocks queries and products that match a list of English and
+// Amharic terms. Base terms come from configuration; managed terms are loaded
+// from the repository on Refresh and added to them.
+type ContentPolicy struct {
+	repo domain.PolicyRepository
+	base []string
+
+	mu    sync.RWMutex
+	terms []string
+}
+
+// NewContentPolicy builds a policy from per-language term lists. Languages
+// missing from terms fall back to DefaultPolicyTerms. repo may be nil.
+func NewContentPolicy(repo domain.PolicyRepository, terms map[string][]string) *ContentPolicy {
+	var base []string
+	for lang, defaults := range DefaultPolicyTerms {
+		list := terms[lang]
+		if len(list) == 0 {
+			list = defaults
+		}
+		base = append(base, list...)
+	}
+	for lang, list := range terms {
+		if _, ok := DefaultPolicyTerms[lang]; !ok {
+			base = append(base, list...)
+		}
+	}
+	base = normalizeTerms(base)
+	return &ContentPolicy{repo: repo, base: base, terms: base}
+}
+
+// Refresh reloads managed terms from the repository. On error the previous
+// list stays in effect.
+func (p *ContentPolicy) Refresh(ctx context.Context) error {
+	if p.repo == nil {
+		return nil
+	}
+	managed, err := p.repo.ListTerms(ctx)
+	if err != nil {
+		return err
+	}
+	terms := append([]string(nil), p.base...)
+	for _, t := range managed {
+		terms = append(terms, t.Term)
+	}
+	terms = normalizeTerms(terms)
+
+	p.mu.Lock()
+	p.terms = terms
+	p.mu.Unlock()
+	return nil
+}
+
+// Match returns the first blocked term found in text.
+func (p *ContentPolicy) Match(text string) (string, bool) {
+	normalized := normalizeQuery(text)
+	if normalized == "" {
+		return "", false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, term := range p.terms {
+		if strings.Contains(normalized, term) {
+			return term, true
+		}
+	}
+	return "", false
+}
+
+// CheckQuery returns domain.ErrPolicyBlocked when query matches a blocked
+// term, recording the attempt against deviceID for abuse review.
+func (p *ContentPolicy) CheckQuery(ctx context.Context, deviceID, query string) error {
+	term, blocked := p.Match(query)
+	if !blocked {
+		return nil
+	}
+	log.Printf("ContentPolicy: blocked query from device %q (term %q): %s", deviceID, term, query)
+	if p.repo != nil {
+		attempt := domain.BlockedAttempt{DeviceID: deviceID, Query: query, Term: term, CreatedAt: time.Now().UTC()}
+		auditCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), policyAuditTimeout)
+		defer cancel()
+		if err := p.repo.RecordBlocked(auditCtx, attempt); err != nil {
+			log.Printf("ContentPolicy: failed to record blocked attempt: %v", err)
+		}
+	}
+	return domain.ErrPolicyBlocked
+}
+
+// FilterProducts drops products whose title or category matches a blocked
+// term and returns the kept products with the number removed.
+func (p *ContentPolicy) FilterProducts(products []*domain.Product) ([]*domain.Product, int) {
+	kept := products[:0:0]
+	for _, prod := range products {
+		if _, blocked := p.Match(prod.Title); blocked {
+			continue
+		}
+		if _, blocked := p.Match(prod.CategoryName); blocked {
+			continue
+		}
+		kept = append(kept, prod)
+	}
+	return kept, len(products) - len(kept)
+}
+
+// normalizeTerms lowercases, collapses whitespace and removes duplicates.
+func normalizeTerms(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, t := range in {
+		t = normalizeQuery(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
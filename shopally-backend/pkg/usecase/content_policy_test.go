@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestContentPolicy_DefaultsCoverEnglishAndAmharic(t *testing.T) {
+	p := NewContentPolicy(nil, nil)
+
+	for _, q := range []string{"Cheap  FIREARMS", "ጠመንጃ ይሸጣል"} {
+		if _, blocked := p.Match(q); !blocked {
+			t.Errorf("%q should be blocked", q)
+		}
+	}
+	if term, blocked := p.Match("wireless earbuds"); blocked {
+		t.Errorf("benign query blocked by %q", term)
+	}
+}
+
+func TestContentPolicy_ConfiguredListReplacesDefaultsPerLanguage(t *testing.T) {
+	p := NewContentPolicy(nil, map[string][]string{"en": {"Vape Pen"}})
+
+	if _, blocked := p.Match("violence movie poster"); blocked {
+		t.Error("configured English list should replace the defaults")
+	}
+	if _, blocked := p.Match("vape   pen kit"); !blocked {
+		t.Error("configured term should be blocked")
+	}
+	if _, blocked := p.Match("ፈንጂ"); !blocked {
+		t.Error("Amharic defaults should remain without an Amharic list")
+	}
+}
+
+func TestContentPolicy_RefreshAddsManagedTerms(t *testing.T) {
+	repo := mocks.NewPolicyRepository(t)
+	repo.On("ListTerms", mock.Anything).Return([]domain.PolicyTerm{{Term: "laser pointer", Lang: "en"}}, nil).Once()
+	repo.On("ListTerms", mock.Anything).Return(nil, errors.New("mongo down")).Once()
+	p := NewContentPolicy(repo, nil)
+
+	if err := p.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if _, blocked := p.Match("green laser pointer"); !blocked {
+		t.Error("managed term should be blocked after refresh")
+	}
+	if err := p.Refresh(context.Background()); err == nil {
+		t.Error("expected refresh error")
+	}
+	if _, blocked := p.Match("green laser pointer"); !blocked {
+		t.Error("failed refresh must keep the previous terms")
+	}
+}
+
+func TestContentPolicy_CheckQueryRecordsAttempt(t *testing.T) {
+	repo := mocks.NewPolicyRepository(t)
+	repo.On("RecordBlocked", mock.Anything, mock.MatchedBy(func(a domain.BlockedAttempt) bool {
+		return a.DeviceID == "device-1" && a.Term == "drugs" && a.Query == "buy drugs" && !a.CreatedAt.IsZero()
+	})).Return(nil).Once()
+	p := NewContentPolicy(repo, nil)
+
+	if err := p.CheckQuery(context.Background(), "device-1", "buy drugs"); !errors.Is(err, domain.ErrPolicyBlocked) {
+		t.Fatalf("expected ErrPolicyBlocked, got %v", err)
+	}
+	if err := p.CheckQuery(context.Background(), "device-1", "usb cable"); err != nil {
+		t.Fatalf("benign query should pass, got %v", err)
+	}
+}
+
+func TestSearch_PolicyDropsBlockedProducts(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{
+		{ID: "1", Title: "Phone case"},
+		{ID: "2", Title: "Replica watch", CategoryName: "Adult Content"},
+		{ID: "3", Title: "Counterfeit bag"},
+	}}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.Policy = NewContentPolicy(nil, nil)
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "accessories"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := ids(res.Products); len(got) != 1 || got[0] != "1" {
+		t.Errorf("blocked products should be dropped, got %v", got)
+	}
+	if res.FilteredOut != 2 {
+		t.Errorf("expected 2 filtered out, got %d", res.FilteredOut)
+	}
+}
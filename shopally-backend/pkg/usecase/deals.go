@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+const (
+	// DefaultDealsCacheTTL is how long a fetched deals pool is served.
+	DefaultDealsCacheTTL = time.Hour
+	// DefaultDealsPoolPages is how many upstream pages make up a deals pool.
+	DefaultDealsPoolPages = 2
+)
+
+// GetDealsUseCase serves the hot-deals feed. Each filter combination maps to
+// a pool of upstream hot products, fetched once (ahead of time by the worker
+// via Prefetch) and cached; requests rank and paginate the pool locally.
+type GetDealsUseCase struct {
+	alibabaGateway domain.AlibabaGateway
+	cacheGateway   domain.CacheGateway
+	fxClient       domain.IFXClient
+
+	// CacheTTL controls the pool cache; it is ignored when no cache gateway
+	// is configured.
+	CacheTTL time.Duration
+	// PoolPages caps the upstream pages, of MaxPageSize products each, in a
+	// pool; pools never grow past the pages a search may rank.
+	PoolPages int
+
+	// Ranker orders the pool; it defaults to the best_deal profile.
+	Ranker Ranker
+	// LandedCost, if set, attaches landed costs before ranking so the price
+	// signal compares landed prices.
+	LandedCost *LandedCostCalculator
+	// Policy, if set, drops products whose title or category is blocked.
+	Policy *ContentPolicy
+	// Affiliate, if set, replaces deeplinks with tracked affiliate redirects.
+	Affiliate *AffiliateLinker
+}
+
+// cachedDeal is a pool product as stored in the cache. It keeps the affiliate
+// fields that Product hides from clients, so cached deals need no new links.
+type cachedDeal struct {
+	*domain.Product
+	PromotionLink  string  `json:"promotionLink,omitempty"`
+	CommissionRate float64 `json:"commissionRate,omitempty"`
+}
+
+// NewGetDealsUseCase creates a new GetDealsUseCase. cg and fx may be nil.
+func NewGetDealsUseCase(ag domain.AlibabaGateway, cg domain.CacheGateway, fx domain.IFXClient) *GetDealsUseCase {
+	return &GetDealsUseCase{
+		alibabaGateway: ag,
+		cacheGateway:   cg,
+		fxClient:       fx,
+		CacheTTL:       DefaultDealsCacheTTL,
+		PoolPages:      DefaultDealsPoolPages,
+		Ranker:         NewRankers(nil)[RankBestDeal],
+		LandedCost:     NewLandedCostCalculator(DefaultLandedCostSettings()),
+	}
+}
+
+// Execute returns one page of deals matching req.Filters, best deals first.
+func (uc *GetDealsUseCase) Execute(ctx context.Context, req domain.DealsRequest) (*domain.DealsResult, error) {
+	page := normalizeSearchRequest(domain.SearchRequest{Page: req.Page, PageSize: req.PageSize})
+
+	quote, fxOK := etbQuote(ctx, uc.fxClient)
+	intent := dealsIntent(req.Filters)
+	for _, w := range intent.Normalize() {
+		if w.Field != "keywords" {
+			log.Println("GetDealsUseCase: corrected filters -", w.Error())
+		}
+	}
+	// Echo filters in the client's currency, before budgets are converted to USD
+	effective := dealsFilters(intent, req.Filters.PromotionName)
+	normalizeBudgetCurrency(&intent, quote, fxOK)
+	filters := dealsFilters(intent, req.Filters.PromotionName)
+
+	pool, err := uc.pool(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.Policy != nil {
+		pool, _ = uc.Policy.FilterProducts(pool)
+	}
+	annotateDelivery(pool)
+	matching, demoted, _ := constraintsFromIntent(intent).split(pool)
+	if uc.LandedCost != nil {
+		uc.LandedCost.Apply(matching, quote, fxOK)
+		uc.LandedCost.Apply(demoted, quote, fxOK)
+	}
+	if uc.Ranker != nil {
+		uc.Ranker.Rank(matching)
+		uc.Ranker.Rank(demoted)
+	}
+	ranked := append(matching, demoted...)
+
+	// page.Page is capped past the end of any pool, so this cannot overflow.
+	start := (page.Page - 1) * page.PageSize
+	products := []*domain.Product{}
+	if start < len(ranked) {
+		products = ranked[start:min(start+page.PageSize, len(ranked))]
+	}
+	if fxOK {
+		applyETBPrices(products, quote)
+	}
+	if uc.Affiliate != nil {
+		uc.Affiliate.Apply(ctx, products, domain.ClickSurfaceDeals)
+	}
+
+	return &domain.DealsResult{
+		Products: products,
+		Page: domain.PageInfo{
+			TotalRecordCount: len(ranked),
+			CurrentPageNo:    page.Page,
+			PageSize:         page.PageSize,
+			NextCursor:       nextPageCursor(page.Page, page.PageSize, len(ranked)),
+		},
+		Filters:    effective,
+		FXDegraded: !fxOK,
+	}, nil
+}
+
+// Prefetch fetches and caches the pool for filters, whose prices must be in
+// USD, and returns its size.
+func (uc *GetDealsUseCase) Prefetch(ctx context.Context, filters domain.DealsFilters) (int, error) {
+	pool, err := uc.fetchPool(ctx, filters)
+	if err != nil {
+		return 0, err
+	}
+	uc.writeCache(ctx, dealsCacheKey(filters), pool)
+	return len(pool), nil
+}
+
+// pool returns the cached pool for filters, fetching and caching it on a miss.
+func (uc *GetDealsUseCase) pool(ctx context.Context, filters domain.DealsFilters) ([]*domain.Product, error) {
+	key := dealsCacheKey(filters)
+	if pool, ok := uc.readCache(ctx, key); ok {
+		return pool, nil
+	}
+	pool, err := uc.fetchPool(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	uc.writeCache(ctx, key, pool)
+	return pool, nil
+}
+
+// fetchPool reads up to PoolPages upstream pages, dropping duplicate products.
+func (uc *GetDealsUseCase) fetchPool(ctx context.Context, filters domain.DealsFilters) ([]*domain.Product, error) {
+	var pool []*domain.Product
+	seen := make(map[string]bool)
+	for pageNo := 1; pageNo <= min(max(uc.PoolPages, 1), maxCandidatePages); pageNo++ {
+		page, err := uc.alibabaGateway.FetchHotProducts(ctx, filters, pageNo, MaxPageSize)
+		if err != nil {
+			if pageNo == 1 {
+				return nil, err
+			}
+			log.Println("GetDealsUseCase: stopping pool fetch at page", pageNo, "error:", err)
+			break
+		}
+		for _, p := range page.Products {
+			if p != nil && !seen[p.ID] {
+				seen[p.ID] = true
+				pool = append(pool, p)
+			}
+		}
+		if len(page.Products) == 0 || pageNo*MaxPageSize >= page.TotalRecordCount {
+			break
+		}
+	}
+	log.Println("GetDealsUseCase: fetched pool of", len(pool), "deals for filters:", filters)
+	return pool, nil
+}
+
+func (uc *GetDealsUseCase) readCache(ctx context.Context, key string) ([]*domain.Product, bool) {
+	if uc.cacheGateway == nil {
+		return nil, false
+	}
+	raw, err := uc.cacheGateway.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			log.Println("GetDealsUseCase: cache read failed for key:", key, "error:", err)
+		}
+		return nil, false
+	}
+	var entries []cachedDeal
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		log.Println("GetDealsUseCase: discarding unreadable cache entry for key:", key)
+		return nil, false
+	}
+	pool := make([]*domain.Product, 0, len(entries))
+	for _, e := range entries {
+		if e.Product == nil {
+			continue
+		}
+		e.Product.PromotionLink, e.Product.CommissionRate = e.PromotionLink, e.CommissionRate
+		pool = append(pool, e.Product)
+	}
+	return pool, true
+}
+
+func (uc *GetDealsUseCase) writeCache(ctx context.Context, key string, pool []*domain.Product) {
+	if uc.cacheGateway == nil {
+		return
+	}
+	entries := make([]cachedDeal, len(pool))
+	for i, p := range pool {
+		entries[i] = cachedDeal{Product: p, PromotionLink: p.PromotionLink, CommissionRate: p.CommissionRate}
+	}
+	if err := uc.cacheGateway.Set(ctx, key, entries, uc.CacheTTL); err != nil {
+		log.Println("GetDealsUseCase: cache write failed for key:", key, "error:", err)
+	}
+}
+
+// dealsIntent expresses the filters as an intent so they share the search
+// validation, currency conversion and local enforcement.
+func dealsIntent(f domain.DealsFilters) domain.SearchIntent {
+	return domain.SearchIntent{CategoryID: f.CategoryID, MinPrice: f.MinPrice, MaxPrice: f.MaxPrice, Currency: f.Currency}
+}
+
+func dealsFilters(intent domain.SearchIntent, promotion string) domain.DealsFilters {
+	return domain.DealsFilters{
+		CategoryID:    intent.CategoryID,
+		MinPrice:      intent.MinPrice,
+		MaxPrice:      intent.MaxPrice,
+		Currency:      intent.Currency,
+		PromotionName: promotion,
+	}
+}
+
+// dealsCacheKey keys a pool by its USD filters.
+func dealsCacheKey(filters domain.DealsFilters) string {
+	raw, _ := json.Marshal(filters)
+	sum := sha256.Sum256(raw)
+	return "deals:" + hex.EncodeToString(sum[:])
+}
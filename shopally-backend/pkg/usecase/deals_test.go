@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func dealsFixture() []*domain.Product {
+	return []*domain.Product{
+		{ID: "small", Price: domain.Price{USD: 9}, OriginalPrice: &domain.Price{USD: 10}},
+		{ID: "big", Price: domain.Price{USD: 10}, OriginalPrice: &domain.Price{USD: 40}},
+		{ID: "reported", Price: domain.Price{USD: 10}, Discount: 50},
+		{ID: "big", Price: domain.Price{USD: 10}, OriginalPrice: &domain.Price{USD: 40}},
+	}
+}
+
+func TestDeals_RanksByDiscountAndPaginatesCachedPool(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: dealsFixture()}
+	uc := NewGetDealsUseCase(ag, newMemCacheGateway(), nil)
+
+	first, err := uc.Execute(context.Background(), domain.DealsRequest{PageSize: 2})
+	if err != nil {
+		t.Fatalf("deals failed: %v", err)
+	}
+	if got := ids(first.Products); len(got) != 2 || got[0] != "big" || got[1] != "reported" {
+		t.Errorf("expected deduped pool ranked by discount, got %v", got)
+	}
+	if first.Page.TotalRecordCount != 3 || first.Page.NextCursor == "" {
+		t.Errorf("unexpected page info: %+v", first.Page)
+	}
+
+	page, size, err := DecodePageCursor(first.Page.NextCursor)
+	if err != nil {
+		t.Fatalf("bad cursor: %v", err)
+	}
+	second, err := uc.Execute(context.Background(), domain.DealsRequest{Page: page, PageSize: size})
+	if err != nil {
+		t.Fatalf("deals failed: %v", err)
+	}
+	if got := ids(second.Products); len(got) != 1 || got[0] != "small" || second.Page.NextCursor != "" {
+		t.Errorf("unexpected last page %v %+v", got, second.Page)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 1 {
+		t.Errorf("expected the pool to be fetched once, got %d", got)
+	}
+	if first.Products[0].LandedCost == nil || first.Products[0].Score.Profile != RankBestDeal {
+		t.Errorf("expected landed cost and best_deal score, got %+v", first.Products[0])
+	}
+}
+
+func TestDeals_ConvertsETBPriceFilters(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: dealsFixture()}
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(100.0, nil).Once()
+	uc := NewGetDealsUseCase(ag, nil, fx)
+
+	maxETB := 950.0
+	res, err := uc.Execute(context.Background(), domain.DealsRequest{Filters: domain.DealsFilters{MaxPrice: &maxETB, Currency: "ETB", PromotionName: "Hot Product"}})
+	if err != nil {
+		t.Fatalf("deals failed: %v", err)
+	}
+	if f := ag.lastDeals; f.MaxPrice == nil || *f.MaxPrice != 9.5 || f.Currency != "USD" || f.PromotionName != "Hot Product" {
+		t.Errorf("expected USD filters upstream, got %+v", f)
+	}
+	if got := ids(res.Products); len(got) != 1 || got[0] != "small" {
+		t.Errorf("expected the price bound enforced locally, got %v", got)
+	}
+	if res.Filters.Currency != "ETB" || *res.Filters.MaxPrice != 950 || res.Products[0].Price.ETB != 900 {
+		t.Errorf("expected filters echoed in ETB and ETB prices, got %+v", res.Filters)
+	}
+}
+
+func TestDeals_PrefetchWarmsCache(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: dealsFixture()}
+	uc := NewGetDealsUseCase(ag, newMemCacheGateway(), nil)
+
+	n, err := uc.Prefetch(context.Background(), domain.DealsFilters{})
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 prefetched deals, got %d (%v)", n, err)
+	}
+	if _, err := uc.Execute(context.Background(), domain.DealsRequest{}); err != nil {
+		t.Fatalf("deals failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 1 {
+		t.Errorf("expected requests to be served from the prefetched pool, got %d fetches", got)
+	}
+}
+
+func TestDeals_CachedPoolKeepsAffiliateFields(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{
+		{ID: "1", DeeplinkURL: "https://www.aliexpress.com/item/1.html", PromotionLink: "https://s.click.aliexpress.com/e/_one", CommissionRate: 7},
+	}}
+	uc := NewGetDealsUseCase(ag, newMemCacheGateway(), nil)
+	uc.Affiliate = NewAffiliateLinker(ag, nil, "secret")
+	if _, err := uc.Prefetch(context.Background(), domain.DealsFilters{}); err != nil {
+		t.Fatalf("prefetch failed: %v", err)
+	}
+
+	res, err := uc.Execute(context.Background(), domain.DealsRequest{})
+	if err != nil || len(res.Products) != 1 {
+		t.Fatalf("deals failed: %v %+v", err, res)
+	}
+	if got := atomic.LoadInt32(&ag.linkCalls); got != 0 {
+		t.Errorf("expected the cached promotion link to be used, got %d link calls", got)
+	}
+	tok, ok := uc.Affiliate.parseLink(res.Products[0].DeeplinkURL)
+	if !ok || tok.TargetURL != "https://s.click.aliexpress.com/e/_one" || tok.CommissionRate != 7 {
+		t.Errorf("expected the cached link and commission in the click token, got %+v", tok)
+	}
+}
+
+func TestDeals_PagePastPoolIsEmpty(t *testing.T) {
+	uc := NewGetDealsUseCase(&fakeAlibabaGateway{products: dealsFixture()}, nil, nil)
+	for _, page := range []int{288230376151711745, math.MaxInt} {
+		res, err := uc.Execute(context.Background(), domain.DealsRequest{Page: page, PageSize: MaxPageSize})
+		if err != nil {
+			t.Fatalf("deals failed: %v", err)
+		}
+		if len(res.Products) != 0 || res.Page.NextCursor != "" {
+			t.Errorf("page %d: expected an empty last page, got %d products and cursor %q", page, len(res.Products), res.Page.NextCursor)
+		}
+	}
+}
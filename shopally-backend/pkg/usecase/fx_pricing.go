@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var errNoFXClient = errors.New("no fx client configured")
+
+// usdToETBQuote returns the current USD->ETB rate and when it was fetched.
+// Clients that cannot report a fetch time are stamped with the call time.
+func usdToETBQuote(ctx context.Context, fx domain.IFXClient) (domain.FXQuote, error) {
+	if fx == nil {
+		return domain.FXQuote{}, errNoFXClient
+	}
+	if qc, ok := fx.(domain.IFXQuoteClient); ok {
+		return qc.GetQuote(ctx, "USD", "ETB")
+	}
+	rate, err := fx.GetRate(ctx, "USD", "ETB")
+	if err != nil {
+		return domain.FXQuote{}, err
+	}
+	return domain.FXQuote{Rate: rate, FetchedAt: time.Now().UTC()}, nil
+}
+
+// etbQuote fetches the USD->ETB quote and reports whether it is usable.
+func etbQuote(ctx context.Context, fx domain.IFXClient) (domain.FXQuote, bool) {
+	quote, err := usdToETBQuote(ctx, fx)
+	if err != nil || quote.Rate <= 0 {
+		log.Println("FX: USD->ETB rate unavailable, returning degraded prices:", err)
+		return domain.FXQuote{}, false
+	}
+	return quote, true
+}
+
+// applyETBPrices fills Price.ETB and Price.FXTimestamp for every product.
+func applyETBPrices(products []*domain.Product, quote domain.FXQuote) {
+	for _, p := range products {
+		if p == nil {
+			continue
+		}
+		p.Price.ETB = roundCents(p.Price.USD * quote.Rate)
+		p.Price.FXTimestamp = quote.FetchedAt
+	}
+}
+
+// normalizeBudgetCurrency converts ETB price bounds in the intent to USD,
+// which is what the AliExpress query uses. When an ETB budget cannot be
+// converted the price bounds are dropped rather than sent as USD. It returns
+// the rate applied, or 0.
+func normalizeBudgetCurrency(intent *domain.SearchIntent, quote domain.FXQuote, fxOK bool) float64 {
+	isETB := intent.Currency == "ETB"
+	if intent.MinPrice == nil && intent.MaxPrice == nil {
+		intent.Currency = ""
+		return 0
+	}
+	intent.Currency = "USD"
+	if !isETB {
+		return 0
+	}
+	if !fxOK {
+		log.Println("FX: dropping ETB price bounds, no rate to convert them:", intent.MinPrice, intent.MaxPrice)
+		intent.MinPrice, intent.MaxPrice = nil, nil
+		intent.Currency = ""
+		return 0
+	}
+
+	for _, bound := range []**float64{&intent.MinPrice, &intent.MaxPrice} {
+		if *bound != nil {
+			usd := roundCents(**bound / quote.Rate)
+			*bound = &usd
+		}
+	}
+	return quote.Rate
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// Ethiopian import tax rates applied when no category rule matches. Duty
+// defaults to the top consumer-goods band; excise only applies to the
+// categories configured for it.
+const (
+	DefaultDutyRate   = 0.30
+	DefaultExciseRate = 0.0
+	DefaultVATRate    = 0.15
+	DefaultSurtaxRate = 0.10
+)
+
+// LandedCostRule sets the duty and excise rates for AliExpress category IDs.
+// A nil rate keeps the default one.
+type LandedCostRule struct {
+	CategoryIDs []string `json:"categoryIds"`
+	Duty        *float64 `json:"duty,omitempty"`
+	Excise      *float64 `json:"excise,omitempty"`
+}
+
+// LandedCostSettings configures the landed-cost engine. Rates are fractions
+// (0.15 is 15%).
+type LandedCostSettings struct {
+	Duty   float64
+	Excise float64
+	VAT    float64
+	Surtax float64
+	// ShippingUSD is the shipping charge assumed when none is known.
+	ShippingUSD float64
+	Rules       []LandedCostRule
+}
+
+// DefaultLandedCostSettings returns the built-in rates with no category rules.
+func DefaultLandedCostSettings() LandedCostSettings {
+	return LandedCostSettings{
+		Duty:   DefaultDutyRate,
+		Excise: DefaultExciseRate,
+		VAT:    DefaultVATRate,
+		Surtax: DefaultSurtaxRate,
+	}
+}
+
+// LandedCostCalculator computes Ethiopian landed costs. Each tax is levied
+// on the customs value (item + shipping) plus the taxes before it: duty on
+// the customs value, excise on that plus duty, surtax on that plus excise and
+// VAT on the surtax-inclusive amount.
+type LandedCostCalculator struct {
+	settings LandedCostSettings
+	rules    map[string]LandedCostRule
+}
+
+// NewLandedCostCalculator builds a calculator. When a category ID appears in
+// several rules the last one wins.
+func NewLandedCostCalculator(settings LandedCostSettings) *LandedCostCalculator {
+	rules := make(map[string]LandedCostRule)
+	for _, r := range settings.Rules {
+		for _, id := range r.CategoryIDs {
+			if id = strings.TrimSpace(id); id != "" {
+				rules[id] = r
+			}
+		}
+	}
+	return &LandedCostCalculator{settings: settings, rules: rules}
+}
+
+// Calculate returns the landed cost of an item. categoryIDs are tried most
+// specific first; ETB amounts are only filled when fxOK.
+func (c *LandedCostCalculator) Calculate(priceUSD float64, shippingUSD *float64, categoryIDs []string, quote domain.FXQuote, fxOK bool) domain.LandedCost {
+	rates := domain.LandedCostRates{Duty: c.settings.Duty, Excise: c.settings.Excise, VAT: c.settings.VAT, Surtax: c.settings.Surtax}
+	matched := ""
+	for _, id := range categoryIDs {
+		if r, ok := c.rules[id]; ok {
+			if r.Duty != nil {
+				rates.Duty = *r.Duty
+			}
+			if r.Excise != nil {
+				rates.Excise = *r.Excise
+			}
+			matched = id
+			break
+		}
+	}
+	shipping := c.settings.ShippingUSD
+	if shippingUSD != nil {
+		shipping = *shippingUSD
+	}
+
+	customs := priceUSD + shipping
+	duty := customs * rates.Duty
+	excise := (customs + duty) * rates.Excise
+	surtax := (customs + duty + excise) * rates.Surtax
+	vat := (customs + duty + excise + surtax) * rates.VAT
+
+	lc := domain.LandedCost{
+		TotalUSD:   roundCents(customs + duty + excise + vat + surtax),
+		Rates:      rates,
+		CategoryID: matched,
+	}
+	if !fxOK {
+		return lc
+	}
+	etb := func(usd float64) float64 { return roundCents(usd * quote.Rate) }
+	lc.ItemETB, lc.ShippingETB = etb(priceUSD), etb(shipping)
+	lc.CustomsValueETB = etb(customs)
+	lc.DutyETB, lc.ExciseETB = etb(duty), etb(excise)
+	lc.VATETB, lc.SurtaxETB = etb(vat), etb(surtax)
+	lc.TotalETB = roundCents(lc.CustomsValueETB + lc.DutyETB + lc.ExciseETB + lc.VATETB + lc.SurtaxETB)
+	lc.FXRate, lc.FXTimestamp = quote.Rate, quote.FetchedAt
+	return lc
+}
+
+// Apply sets LandedCost on every product with a known price.
+func (c *LandedCostCalculator) Apply(products []*domain.Product, quote domain.FXQuote, fxOK bool) {
+	for _, p := range products {
+		if p == nil || p.Price.USD <= 0 {
+			continue
+		}
+		lc := c.Calculate(p.Price.USD, nil, productCategoryIDs(p), quote, fxOK)
+		p.LandedCost = &lc
+	}
+}
+
+// productCategoryIDs returns the product's category IDs, deepest level first.
+func productCategoryIDs(p *domain.Product) []string {
+	cats := append([]domain.ProductCategory(nil), p.Categories...)
+	sort.SliceStable(cats, func(i, j int) bool { return cats[i].Level > cats[j].Level })
+	ids := make([]string, 0, len(cats))
+	for _, cat := range cats {
+		ids = append(ids, cat.ID)
+	}
+	return ids
+}
+
+// ErrInvalidLandedCostRequest is returned for a request without a positive
+// price or with a negative shipping charge.
+var ErrInvalidLandedCostRequest = errors.New("priceUsd must be positive and shippingUsd must not be negative")
+
+// EstimateLandedCostUseCase prices a single item with the landed-cost engine.
+type EstimateLandedCostUseCase struct {
+	calc     *LandedCostCalculator
+	fxClient domain.IFXClient
+}
+
+// NewEstimateLandedCostUseCase creates a new EstimateLandedCostUseCase.
+func NewEstimateLandedCostUseCase(calc *LandedCostCalculator, fx domain.IFXClient) *EstimateLandedCostUseCase {
+	return &EstimateLandedCostUseCase{calc: calc, fxClient: fx}
+}
+
+// Execute returns the landed cost for req at the current USD->ETB rate.
+func (uc *EstimateLandedCostUseCase) Execute(ctx context.Context, req domain.LandedCostRequest) (*domain.LandedCostEstimate, error) {
+	if req.PriceUSD <= 0 || (req.ShippingUSD != nil && *req.ShippingUSD < 0) {
+		return nil, ErrInvalidLandedCostRequest
+	}
+	quote, fxOK := etbQuote(ctx, uc.fxClient)
+	lc := uc.calc.Calculate(req.PriceUSD, req.ShippingUSD, req.CategoryIDs, quote, fxOK)
+	return &domain.LandedCostEstimate{LandedCost: lc, FXDegraded: !fxOK}, nil
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLandedCost_CompoundsEthiopianTaxes(t *testing.T) {
+	quote := domain.FXQuote{Rate: 100, FetchedAt: time.Now()}
+	shipping := 10.0
+
+	// Expected USD amounts, worked by hand: duty on customs, excise on
+	// customs+duty, surtax on customs+duty+excise, VAT on all of those.
+	cases := []struct {
+		name                                      string
+		settings                                  LandedCostSettings
+		price                                     float64
+		shipping                                  *float64
+		customs, duty, excise, surtax, vat, total float64
+	}{
+		{
+			// 100; 30; 0; 130*0.1 = 13; 143*0.15 = 21.45
+			name:     "default shipping",
+			settings: LandedCostSettings{Duty: 0.3, VAT: 0.15, Surtax: 0.1, ShippingUSD: 5},
+			price:    95,
+			customs:  100, duty: 30, excise: 0, surtax: 13, vat: 21.45, total: 164.45,
+		},
+		{
+			// 100; 20; 120*0.1 = 12; 132*0.1 = 13.2; 145.2*0.15 = 21.78
+			name:     "with excise",
+			settings: LandedCostSettings{Duty: 0.2, Excise: 0.1, VAT: 0.15, Surtax: 0.1},
+			price:    100,
+			customs:  100, duty: 20, excise: 12, surtax: 13.2, vat: 21.78, total: 166.98,
+		},
+		{
+			// 40+10 = 50; 5; 0; 0; 55*0.15 = 8.25
+			name:     "explicit shipping, no surtax",
+			settings: LandedCostSettings{Duty: 0.1, VAT: 0.15, ShippingUSD: 99},
+			price:    40,
+			shipping: &shipping,
+			customs:  50, duty: 5, excise: 0, surtax: 0, vat: 8.25, total: 63.25,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lc := NewLandedCostCalculator(tc.settings).Calculate(tc.price, tc.shipping, nil, quote, true)
+			want := map[string][2]float64{
+				"customs": {lc.CustomsValueETB, tc.customs * 100},
+				"duty":    {lc.DutyETB, tc.duty * 100},
+				"excise":  {lc.ExciseETB, tc.excise * 100},
+				"surtax":  {lc.SurtaxETB, tc.surtax * 100},
+				"vat":     {lc.VATETB, tc.vat * 100},
+				"total":   {lc.TotalETB, tc.total * 100},
+				"usd":     {lc.TotalUSD, tc.total},
+			}
+			for name, v := range want {
+				if math.Abs(v[0]-v[1]) > 1e-6 {
+					t.Errorf("%s: got %v, want %v", name, v[0], v[1])
+				}
+			}
+			if lc.FXRate != 100 || lc.FXTimestamp.IsZero() {
+				t.Errorf("expected FX provenance, got rate %v at %v", lc.FXRate, lc.FXTimestamp)
+			}
+		})
+	}
+}
+
+func TestLandedCost_CategoryRulesPreferDeepestLevel(t *testing.T) {
+	calc := NewLandedCostCalculator(LandedCostSettings{
+		Duty: 0.3,
+		Rules: []LandedCostRule{
+			{CategoryIDs: []string{"44"}, Duty: floatPtr(0.1)},
+			{CategoryIDs: []string{"63705"}, Duty: floatPtr(0.05), Excise: floatPtr(0.1)},
+		},
+	})
+	p := &domain.Product{ID: "1", Price: domain.Price{USD: 10}, Categories: []domain.ProductCategory{
+		{ID: "44", Level: 1},
+		{ID: "63705", Level: 2},
+	}}
+	other := &domain.Product{ID: "2", Price: domain.Price{USD: 10}}
+	calc.Apply([]*domain.Product{p, other, {ID: "unpriced"}}, domain.FXQuote{}, false)
+
+	if p.LandedCost == nil || p.LandedCost.CategoryID != "63705" || p.LandedCost.Rates.Excise != 0.1 {
+		t.Fatalf("expected the second-level rule, got %+v", p.LandedCost)
+	}
+	if other.LandedCost.CategoryID != "" || other.LandedCost.Rates.Duty != 0.3 {
+		t.Errorf("expected the default rule, got %+v", other.LandedCost)
+	}
+	if p.LandedCost.TotalETB != 0 || p.LandedCost.TotalUSD == 0 {
+		t.Errorf("without a rate only the USD total is set, got %+v", p.LandedCost)
+	}
+}
+
+func TestLandedCost_PartialRuleKeepsDefaultRates(t *testing.T) {
+	calc := NewLandedCostCalculator(LandedCostSettings{
+		Duty:   0.3,
+		Excise: 0.05,
+		Rules: []LandedCostRule{
+			{CategoryIDs: []string{"excise-only"}, Excise: floatPtr(0.2)},
+			{CategoryIDs: []string{"duty-only"}, Duty: floatPtr(0.1)},
+		},
+	})
+
+	lc := calc.Calculate(100, floatPtr(0), []string{"excise-only"}, domain.FXQuote{}, false)
+	if lc.CategoryID != "excise-only" || lc.Rates.Duty != 0.3 || lc.Rates.Excise != 0.2 {
+		t.Errorf("expected the default duty with the rule's excise, got %+v", lc.Rates)
+	}
+	lc = calc.Calculate(100, floatPtr(0), []string{"duty-only"}, domain.FXQuote{}, false)
+	if lc.CategoryID != "duty-only" || lc.Rates.Duty != 0.1 || lc.Rates.Excise != 0.05 {
+		t.Errorf("expected the rule's duty with the default excise, got %+v", lc.Rates)
+	}
+}
+
+func TestRankers_CheapestComparesLandedCost(t *testing.T) {
+	calc := NewLandedCostCalculator(LandedCostSettings{
+		Duty:  0.05,
+		Rules: []LandedCostRule{{CategoryIDs: []string{"7"}, Duty: floatPtr(0.35), Excise: floatPtr(1)}},
+	})
+	products := []*domain.Product{
+		{ID: "taxed", Price: domain.Price{USD: 10}, Categories: []domain.ProductCategory{{ID: "7", Level: 1}}},
+		{ID: "plain", Price: domain.Price{USD: 12}},
+	}
+	calc.Apply(products, domain.FXQuote{}, false)
+	NewRankers(nil)[RankCheapest].Rank(products)
+
+	if products[0].ID != "plain" {
+		t.Errorf("expected the lower landed cost first, got %s", products[0].ID)
+	}
+}
+
+func TestEstimateLandedCost(t *testing.T) {
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(0.0, errors.New("provider down")).Once()
+	uc := NewEstimateLandedCostUseCase(NewLandedCostCalculator(DefaultLandedCostSettings()), fx)
+
+	shipping := 2.0
+	res, err := uc.Execute(context.Background(), domain.LandedCostRequest{PriceUSD: 8, ShippingUSD: &shipping})
+	if err != nil {
+		t.Fatalf("estimate failed: %v", err)
+	}
+	if !res.FXDegraded || res.LandedCost.TotalUSD <= 10 {
+		t.Errorf("unexpected estimate: %+v", res)
+	}
+
+	negative := -1.0
+	for _, req := range []domain.LandedCostRequest{{}, {PriceUSD: 5, ShippingUSD: &negative}} {
+		if _, err := uc.Execute(context.Background(), req); !errors.Is(err, ErrInvalidLandedCostRequest) {
+			t.Errorf("%+v: expected ErrInvalidLandedCostRequest, got %v", req, err)
+		}
+	}
+}
+
+func TestSearch_AttachesLandedCost(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1", Price: domain.Price{USD: 10}}}}
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(50.0, nil).Once()
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, fx)
+
+	res, err := uc.Search(searchCtx("am"), domain.SearchRequest{Query: "phone"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	lc := res.Products[0].LandedCost
+	if lc == nil || lc.ItemETB != 500 || lc.TotalETB <= lc.ItemETB {
+		t.Errorf("expected an ETB landed cost above the item price, got %+v", lc)
+	}
+}
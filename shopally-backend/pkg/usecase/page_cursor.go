@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultPageSize is used when the client does not ask for a page size.
+	DefaultPageSize = 10
+	// MaxPageSize is the largest page the AliExpress affiliate API serves.
+	MaxPageSize = 50
+)
+
+// ErrInvalidCursor is returned when a page cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// EncodePageCursor returns an opaque cursor pointing at the given page.
+func EncodePageCursor(page, pageSize int) string {
+	raw := fmt.Sprintf("p%d:s%d", page, pageSize)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodePageCursor reverses EncodePageCursor.
+func DecodePageCursor(cursor string) (int, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+	var page, size int
+	if _, err := fmt.Sscanf(string(raw), "p%d:s%d", &page, &size); err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+	if page < 1 || size < 1 || size > MaxPageSize || page > LastPage(size) {
+		return 0, 0, ErrInvalidCursor
+	}
+	return page, size, nil
+}
+
+// LastPage returns the last page of pageSize results that holds any of the
+// results a search or deals pool can reach.
+func LastPage(pageSize int) int {
+	return (maxPoolResults + pageSize - 1) / pageSize
+}
+
+// nextPageCursor returns the cursor for the page after the given one, or ""
+// when the upstream result set has been exhausted.
+func nextPageCursor(page, pageSize, total int) string {
+	if pageSize <= 0 || page*pageSize >= total {
+		return ""
+	}
+	return EncodePageCursor(page+1, pageSize)
+}
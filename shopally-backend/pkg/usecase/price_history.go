@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+const (
+	// DefaultPricePointInterval is the minimum time between two recorded
+	// points of an unchanged price.
+	DefaultPricePointInterval = time.Hour
+	// DefaultPriceHistoryDays is the range returned when none is requested.
+	DefaultPriceHistoryDays = 30
+	// MaxPriceHistoryDays caps the range of a price history request.
+	MaxPriceHistoryDays = 366
+	// DefaultMaxPendingPriceWrites caps the background price writes in flight.
+	DefaultMaxPendingPriceWrites = 8
+
+	priceRecordTimeout = 10 * time.Second
+)
+
+// ErrInvalidDateRange is returned for a price history range that is empty,
+// reversed or longer than MaxPriceHistoryDays.
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// PriceTracker persists snapshots of fetched products and appends their
+// prices to the price history.
+type PriceTracker struct {
+	repo domain.ProductRepository
+
+	// MinInterval throttles points per product: an unchanged price is
+	// recorded at most once per interval by this process.
+	MinInterval time.Duration
+	// MaxPendingWrites caps the RecordInBackground writes in flight; batches
+	// arriving while it is reached are dropped and left to a later search.
+	MaxPendingWrites int
+
+	mu        sync.Mutex
+	last      map[string]trackedPrice // product ID -> last stored price
+	lastSweep time.Time
+	pending   int
+}
+
+type trackedPrice struct {
+	usd float64
+	at  time.Time
+}
+
+// NewPriceTracker creates a new PriceTracker.
+func NewPriceTracker(repo domain.ProductRepository) *PriceTracker {
+	return &PriceTracker{
+		repo:             repo,
+		MinInterval:      DefaultPricePointInterval,
+		MaxPendingWrites: DefaultMaxPendingPriceWrites,
+		last:             make(map[string]trackedPrice),
+	}
+}
+
+// Record stores the products and their current prices. ETB prices are
+// derived from quote when fxOK.
+func (t *PriceTracker) Record(ctx context.Context, products []*domain.Product, quote domain.FXQuote, fxOK bool) error {
+	snapshots, points := t.prepare(products, quote, fxOK)
+	if len(snapshots) == 0 {
+		return nil
+	}
+	if err := t.repo.SaveSnapshots(ctx, snapshots, points); err != nil {
+		return err
+	}
+	t.markStored(points)
+	return nil
+}
+
+// RecordInBackground is Record without blocking the caller or sharing its
+// cancellation. The products are copied before it returns. When
+// MaxPendingWrites writes are already in flight the batch is dropped; its
+// prices are not throttled, so a later search records them.
+func (t *PriceTracker) RecordInBackground(ctx context.Context, products []*domain.Product, quote domain.FXQuote, fxOK bool) {
+	snapshots, points := t.prepare(products, quote, fxOK)
+	if len(snapshots) == 0 {
+		return
+	}
+	if !t.startWrite() {
+		log.Println("PriceTracker: dropping", len(points), "price points,", t.MaxPendingWrites, "writes already in flight")
+		return
+	}
+	bctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), priceRecordTimeout)
+	go func() {
+		defer cancel()
+		defer t.endWrite()
+		if err := t.repo.SaveSnapshots(bctx, snapshots, points); err != nil {
+			log.Println("PriceTracker: failed to record", len(points), "price points, error:", err)
+			return
+		}
+		t.markStored(points)
+	}()
+}
+
+// startWrite reserves a background write, reporting false when
+// MaxPendingWrites are in flight.
+func (t *PriceTracker) startWrite() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending >= max(t.MaxPendingWrites, 1) {
+		return false
+	}
+	t.pending++
+	return true
+}
+
+func (t *PriceTracker) endWrite() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending--
+}
+
+// prepare copies the products not throttled by MinInterval and builds their
+// price points.
+func (t *PriceTracker) prepare(products []*domain.Product, quote domain.FXQuote, fxOK bool) ([]*domain.Product, []domain.PricePoint) {
+	now := time.Now().UTC()
+	var snapshots []*domain.Product
+	var points []domain.PricePoint
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, p := range products {
+		if p == nil || p.ID == "" || p.Price.USD <= 0 {
+			continue
+		}
+		if tp, ok := t.last[p.ID]; ok && tp.usd == p.Price.USD && now.Sub(tp.at) < t.MinInterval {
+			continue
+		}
+
+		cp := *p
+		snapshots = append(snapshots, &cp)
+		pt := domain.PricePoint{ProductID: p.ID, USD: p.Price.USD, Discount: discountPercent(p), At: now}
+		if fxOK {
+			pt.ETB = roundCents(p.Price.USD * quote.Rate)
+		}
+		points = append(points, pt)
+	}
+	return snapshots, points
+}
+
+// markStored remembers the stored points for throttling. Entries older than
+// MinInterval no longer throttle anything and are swept once per interval,
+// so only products seen recently are kept.
+func (t *PriceTracker) markStored(points []domain.PricePoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, pt := range points {
+		t.last[pt.ProductID] = trackedPrice{usd: pt.USD, at: pt.At}
+	}
+	now := time.Now()
+	if now.Sub(t.lastSweep) < t.MinInterval {
+		return
+	}
+	for id, tp := range t.last {
+		if now.Sub(tp.at) >= t.MinInterval {
+			delete(t.last, id)
+		}
+	}
+	t.lastSweep = now
+}
+
+// GetPriceHistoryUseCase serves the daily price series of a product.
+type GetPriceHistoryUseCase struct {
+	repo domain.ProductRepository
+}
+
+// NewGetPriceHistoryUseCase creates a new GetPriceHistoryUseCase.
+func NewGetPriceHistoryUseCase(repo domain.ProductRepository) *GetPriceHistoryUseCase {
+	return &GetPriceHistoryUseCase{repo: repo}
+}
+
+// Execute returns the daily min, max and close prices of the product over
+// [from, to). A zero to means now and a zero from DefaultPriceHistoryDays
+// before to. Days without points are omitted.
+func (uc *GetPriceHistoryUseCase) Execute(ctx context.Context, productID string, from, to time.Time) (*domain.PriceHistory, error) {
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -DefaultPriceHistoryDays)
+	}
+	if !from.Before(to) || to.Sub(from) > MaxPriceHistoryDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+
+	points, err := uc.repo.PricePoints(ctx, productID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PriceHistory{
+		ProductID: productID,
+		From:      from.UTC(),
+		To:        to.UTC(),
+		Days:      dailyPrices(points),
+	}, nil
+}
+
+// dailyPrices groups time-ordered points by UTC day.
+func dailyPrices(points []domain.PricePoint) []domain.DailyPrice {
+	days := []domain.DailyPrice{}
+	for _, pt := range points {
+		date := pt.At.UTC().Format("2006-01-02")
+		if n := len(days); n == 0 || days[n-1].Date != date {
+			days = append(days, domain.DailyPrice{Date: date, MinUSD: pt.USD, MaxUSD: pt.USD})
+		}
+		d := &days[len(days)-1]
+		d.MinUSD, d.MaxUSD, d.CloseUSD = min(d.MinUSD, pt.USD), max(d.MaxUSD, pt.USD), pt.USD
+		if pt.ETB > 0 {
+			if d.MinETB == 0 || pt.ETB < d.MinETB {
+				d.MinETB = pt.ETB
+			}
+			d.MaxETB, d.CloseETB = max(d.MaxETB, pt.ETB), pt.ETB
+		}
+		d.Points++
+	}
+	return days
+}
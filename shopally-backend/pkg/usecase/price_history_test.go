@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPriceHistory_AggregatesDailyMinMaxClose(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := mocks.NewProductRepository(t)
+	repo.On("PricePoints", mock.Anything, "42", day, day.AddDate(0, 0, 2)).Return([]domain.PricePoint{
+		{USD: 10, ETB: 1000, At: day.Add(1 * time.Hour)},
+		{USD: 8, At: day.Add(2 * time.Hour)},
+		{USD: 12, ETB: 1250, At: day.Add(3 * time.Hour)},
+		{USD: 11, ETB: 1150, At: day.Add(25 * time.Hour)},
+	}, nil).Once()
+
+	h, err := NewGetPriceHistoryUseCase(repo).Execute(context.Background(), "42", day, day.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("price history failed: %v", err)
+	}
+	want := []domain.DailyPrice{
+		{Date: "2025-03-01", MinUSD: 8, MaxUSD: 12, CloseUSD: 12, MinETB: 1000, MaxETB: 1250, CloseETB: 1250, Points: 3},
+		{Date: "2025-03-02", MinUSD: 11, MaxUSD: 11, CloseUSD: 11, MinETB: 1150, MaxETB: 1150, CloseETB: 1150, Points: 1},
+	}
+	if len(h.Days) != len(want) {
+		t.Fatalf("expected %d days, got %+v", len(want), h.Days)
+	}
+	for i := range want {
+		if h.Days[i] != want[i] {
+			t.Errorf("day %d: expected %+v, got %+v", i, want[i], h.Days[i])
+		}
+	}
+}
+
+func TestPriceHistory_RejectsInvalidRanges(t *testing.T) {
+	uc := NewGetPriceHistoryUseCase(mocks.NewProductRepository(t))
+	now := time.Now()
+	for name, r := range map[string][2]time.Time{
+		"reversed":  {now, now.Add(-time.Hour)},
+		"too long":  {now.AddDate(-2, 0, 0), now},
+		"from only": {now.Add(time.Hour), time.Time{}},
+	} {
+		if _, err := uc.Execute(context.Background(), "42", r[0], r[1]); !errors.Is(err, ErrInvalidDateRange) {
+			t.Errorf("%s: expected ErrInvalidDateRange, got %v", name, err)
+		}
+	}
+}
+
+func TestPriceTracker_ThrottlesUnchangedPrices(t *testing.T) {
+	repo := mocks.NewProductRepository(t)
+	var saved [][]domain.PricePoint
+	repo.On("SaveSnapshots", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(2).([]domain.PricePoint)) }).
+		Return(nil)
+	tracker := NewPriceTracker(repo)
+	quote := domain.FXQuote{Rate: 100}
+
+	products := []*domain.Product{{ID: "1", Price: domain.Price{USD: 10}}, {ID: "2", Price: domain.Price{USD: 5}}, {ID: "free"}}
+	if err := tracker.Record(context.Background(), products, quote, true); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+	products[1].Price.USD = 4
+	if err := tracker.Record(context.Background(), products, quote, false); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	if len(saved) != 2 || len(saved[0]) != 2 || saved[0][0].ETB != 1000 {
+		t.Fatalf("expected both priced products recorded with ETB first, got %+v", saved)
+	}
+	if len(saved[1]) != 1 || saved[1][0].ProductID != "2" || saved[1][0].USD != 4 || saved[1][0].ETB != 0 {
+		t.Errorf("expected only the changed price recorded again, got %+v", saved[1])
+	}
+}
+
+func TestPriceTracker_RetriesFailedWritesAndForgetsOldPrices(t *testing.T) {
+	repo := mocks.NewProductRepository(t)
+	repo.On("SaveSnapshots", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("mongo down")).Once()
+	repo.On("SaveSnapshots", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	tracker := NewPriceTracker(repo)
+	tracker.MinInterval = 20 * time.Millisecond
+
+	products := []*domain.Product{{ID: "1", Price: domain.Price{USD: 10}}}
+	if err := tracker.Record(context.Background(), products, domain.FXQuote{}, false); err == nil {
+		t.Fatal("expected the failed write to be reported")
+	}
+	// The failed point is not throttled.
+	if err := tracker.Record(context.Background(), products, domain.FXQuote{}, false); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := tracker.Record(context.Background(), []*domain.Product{{ID: "2", Price: domain.Price{USD: 5}}}, domain.FXQuote{}, false); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if _, ok := tracker.last["1"]; ok || len(tracker.last) != 1 {
+		t.Errorf("expected prices older than the interval to be swept, got %v", tracker.last)
+	}
+}
+
+func TestPriceTracker_DropsBackgroundWritesWhenBusy(t *testing.T) {
+	repo := mocks.NewProductRepository(t)
+	release := make(chan struct{})
+	repo.On("SaveSnapshots", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(nil).Twice()
+	tracker := NewPriceTracker(repo)
+	tracker.MaxPendingWrites = 1
+	pending := func() int {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		return tracker.pending
+	}
+
+	tracker.RecordInBackground(context.Background(), []*domain.Product{{ID: "1", Price: domain.Price{USD: 10}}}, domain.FXQuote{}, false)
+	// The second batch finds the only write slot taken and is dropped.
+	tracker.RecordInBackground(context.Background(), []*domain.Product{{ID: "2", Price: domain.Price{USD: 5}}}, domain.FXQuote{}, false)
+	close(release)
+	for pending() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The dropped price was not throttled and is written with the next batch.
+	tracker.RecordInBackground(context.Background(), []*domain.Product{{ID: "2", Price: domain.Price{USD: 5}}}, domain.FXQuote{}, false)
+	for pending() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if len(tracker.last) != 2 {
+		t.Errorf("expected both prices stored, got %v", tracker.last)
+	}
+}
+
+func TestSearch_RecordsFetchedPrices(t *testing.T) {
+	repo := mocks.NewProductRepository(t)
+	recorded := make(chan []domain.PricePoint, 1)
+	repo.On("SaveSnapshots", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { recorded <- args.Get(2).([]domain.PricePoint) }).
+		Return(nil).Once()
+
+	ag := &fakeAlibabaGateway{products: []*domain.Product{
+		{ID: "a", Price: domain.Price{USD: 3}},
+		{ID: "b", Price: domain.Price{USD: 7}},
+		{ID: "blocked", Title: "firearm kit", Price: domain.Price{USD: 9}},
+	}}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.Prices = NewPriceTracker(repo)
+	uc.Policy = NewContentPolicy(nil, map[string][]string{"en": {"firearm"}})
+	if _, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "cable"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	select {
+	case points := <-recorded:
+		if len(points) != 2 {
+			t.Errorf("expected a point per allowed product, got %+v", points)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fetched prices were not recorded")
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// DefaultProductCacheTTL is how long a fetched product detail is cached.
+const DefaultProductCacheTTL = 30 * time.Minute
+
+// GetProductDetailUseCase fetches a single product with its full field set,
+// prices it in ETB and optionally summarizes it with the LLM.
+type GetProductDetailUseCase struct {
+	alibabaGateway domain.AlibabaGateway
+	llmGateway     domain.LLMGateway
+	cacheGateway   domain.CacheGateway
+	fxClient       domain.IFXClient
+
+	// CacheTTL controls the product cache; it is ignored when no cache
+	// gateway is configured.
+	CacheTTL time.Duration
+	// SummaryTimeout bounds the LLM summary; on timeout the heuristic
+	// fallback summary is returned.
+	SummaryTimeout time.Duration
+	// LandedCost, if set, attaches the Ethiopian landed cost to the product.
+	LandedCost *LandedCostCalculator
+	// Affiliate, if set, replaces the deeplink with a tracked affiliate
+	// redirect before the product is cached.
+	Affiliate *AffiliateLinker
+}
+
+// NewGetProductDetailUseCase creates a new GetProductDetailUseCase. lg, cg and
+// fx may be nil.
+func NewGetProductDetailUseCase(ag domain.AlibabaGateway, lg domain.LLMGateway, cg domain.CacheGateway, fx domain.IFXClient) *GetProductDetailUseCase {
+	return &GetProductDetailUseCase{
+		alibabaGateway: ag,
+		llmGateway:     lg,
+		cacheGateway:   cg,
+		fxClient:       fx,
+		CacheTTL:       DefaultProductCacheTTL,
+		SummaryTimeout: DefaultSummaryTimeout,
+		LandedCost:     NewLandedCostCalculator(DefaultLandedCostSettings()),
+	}
+}
+
+// Execute returns the product with the given ID. Products are cached in USD
+// so ETB prices always reflect the current rate; summarized products are
+// cached per response language.
+func (uc *GetProductDetailUseCase) Execute(ctx context.Context, productID string, withSummary bool) (*domain.ProductDetail, error) {
+	key := productCacheKey(ctx, productID, withSummary)
+	product, ok := uc.readCache(ctx, key)
+	if !ok {
+		fetched, err := uc.alibabaGateway.FetchProductDetail(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		if fetched == nil {
+			return nil, domain.ErrProductNotFound
+		}
+		product = fetched
+		annotateDelivery([]*domain.Product{product})
+		if withSummary && uc.llmGateway != nil {
+			product = uc.summarize(ctx, product)
+		}
+		if uc.Affiliate != nil {
+			uc.Affiliate.Apply(ctx, []*domain.Product{product}, domain.ClickSurfaceProduct)
+		}
+		uc.writeCache(ctx, key, product)
+	}
+
+	quote, fxOK := etbQuote(ctx, uc.fxClient)
+	if fxOK {
+		applyETBPrices([]*domain.Product{product}, quote)
+		if product.OriginalPrice != nil {
+			product.OriginalPrice.ETB = roundCents(product.OriginalPrice.USD * quote.Rate)
+			product.OriginalPrice.FXTimestamp = quote.FetchedAt
+		}
+	}
+	if uc.LandedCost != nil {
+		uc.LandedCost.Apply([]*domain.Product{product}, quote, fxOK)
+	}
+	return &domain.ProductDetail{Product: product, FXDegraded: !fxOK}, nil
+}
+
+func (uc *GetProductDetailUseCase) summarize(ctx context.Context, p *domain.Product) *domain.Product {
+	sctx, cancel := context.WithTimeout(ctx, uc.SummaryTimeout)
+	defer cancel()
+	enhanced, err := uc.llmGateway.SummarizeProduct(sctx, p, p.Title)
+	if err != nil || enhanced == nil {
+		log.Println("GetProductDetailUseCase: summarization failed for product:", p.ID, "error:", err)
+		return uc.llmGateway.FallbackSummary(ctx, p, p.Title)
+	}
+	return enhanced
+}
+
+func (uc *GetProductDetailUseCase) readCache(ctx context.Context, key string) (*domain.Product, bool) {
+	if uc.cacheGateway == nil {
+		return nil, false
+	}
+	raw, err := uc.cacheGateway.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			log.Println("GetProductDetailUseCase: cache read failed for key:", key, "error:", err)
+		}
+		return nil, false
+	}
+	var p domain.Product
+	if err := json.Unmarshal([]byte(raw), &p); err != nil || p.ID == "" {
+		log.Println("GetProductDetailUseCase: discarding unreadable cache entry for key:", key)
+		return nil, false
+	}
+	return &p, true
+}
+
+func (uc *GetProductDetailUseCase) writeCache(ctx context.Context, key string, p *domain.Product) {
+	if uc.cacheGateway == nil {
+		return
+	}
+	if err := uc.cacheGateway.Set(ctx, key, p, uc.CacheTTL); err != nil {
+		log.Println("GetProductDetailUseCase: cache write failed for key:", key, "error:", err)
+	}
+}
+
+// productCacheKey keys plain products by ID alone and summarized ones by ID
+// and response language.
+func productCacheKey(ctx context.Context, productID string, withSummary bool) string {
+	if !withSummary {
+		return "product:" + productID
+	}
+	lang, _ := ctx.Value(contextkeys.RespLang).(string)
+	return "product:" + productID + ":summary:" + strings.ToLower(lang)
+}
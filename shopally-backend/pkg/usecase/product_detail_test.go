@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductDetail_CachesAndPricesInETB(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{
+		ID:               "1",
+		Title:            "Earbuds",
+		Price:            domain.Price{USD: 10},
+		OriginalPrice:    &domain.Price{USD: 20},
+		DeliveryEstimate: "15-30 days",
+	}}}
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(100.0, nil).Once()
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(120.0, nil).Once()
+	uc := NewGetProductDetailUseCase(ag, &fakeLLMGateway{}, newMemCacheGateway(), fx)
+
+	if _, err := uc.Execute(searchCtx("en"), "1", false); err != nil {
+		t.Fatalf("first fetch failed: %v", err)
+	}
+	res, err := uc.Execute(searchCtx("en"), "1", false)
+	if err != nil {
+		t.Fatalf("second fetch failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 1 {
+		t.Errorf("expected 1 upstream fetch, got %d", got)
+	}
+	p := res.Product
+	if p.Price.ETB != 1200 || p.OriginalPrice.ETB != 2400 {
+		t.Errorf("cached product should be repriced at the current rate, got %+v / %+v", p.Price, *p.OriginalPrice)
+	}
+	if p.DeliveryMinDays != 15 || p.DeliveryMaxDays != 30 {
+		t.Errorf("expected parsed delivery bounds, got %d-%d", p.DeliveryMinDays, p.DeliveryMaxDays)
+	}
+	if res.FXDegraded || p.AIEnriched {
+		t.Errorf("unexpected flags: fxDegraded=%v aiEnriched=%v", res.FXDegraded, p.AIEnriched)
+	}
+}
+
+func TestProductDetail_SummaryFallsBackOnTimeout(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1", Title: "Earbuds"}}}
+	lg := &fakeLLMGateway{delay: time.Second}
+	uc := NewGetProductDetailUseCase(ag, lg, nil, nil)
+	uc.SummaryTimeout = 10 * time.Millisecond
+
+	res, err := uc.Execute(searchCtx("en"), "1", true)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if res.Product.AIEnriched || !res.FXDegraded {
+		t.Errorf("expected fallback summary and degraded FX, got %+v", res)
+	}
+
+	lg.delay = 0
+	res, err = uc.Execute(searchCtx("en"), "1", true)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if !res.Product.AIEnriched {
+		t.Error("expected an LLM summary")
+	}
+}
+
+func TestProductDetail_NotFound(t *testing.T) {
+	uc := NewGetProductDetailUseCase(&fakeAlibabaGateway{}, nil, nil, nil)
+
+	_, err := uc.Execute(context.Background(), "404", false)
+	if !errors.Is(err, domain.ErrProductNotFound) {
+		t.Fatalf("expected ErrProductNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// Built-in ranking profiles selectable with ?sort=. RankBestDeal orders the
+// deals feed; RankPopular favours products viewed most in the popular window.
+const (
+	RankBestMatch = "best_match"
+	RankCheapest  = "cheapest"
+	RankFastest   = "fastest"
+	RankBestRated = "best_rated"
+	RankMostSold  = "most_sold"
+	RankBestDeal  = "best_deal"
+	RankPopular   = "popular"
+)
+
+// Score component names reported in domain.ScoreBreakdown.
+const (
+	signalRelevance  = "relevance"
+	signalPrice      = "price"
+	signalDelivery   = "delivery"
+	signalRating     = "rating"
+	signalSeller     = "seller"
+	signalSales      = "sales"
+	signalDiscount   = "discount"
+	signalPopularity = "popularity"
+)
+
+// Ranker orders a candidate set in place and attaches a score breakdown to
+// every product so clients can explain the order.
+type Ranker interface {
+	Rank(products []*domain.Product)
+}
+
+// RankWeights are the relative weights of each normalized (0..100) signal.
+// They need not sum to 1; the total is divided by the weight sum.
+type RankWeights struct {
+	Relevance float64 `json:"relevance"`
+	Price     float64 `json:"price"`
+	Delivery  float64 `json:"delivery"`
+	Rating    float64 `json:"rating"`
+	Seller    float64 `json:"seller"`
+	Sales     float64 `json:"sales"`
+	Discount  float64 `json:"discount"`
+	// Popularity weighs recent views; see ProductViewsUseCase.AnnotatePopularity.
+	Popularity float64 `json:"popularity"`
+}
+
+func (w RankWeights) sum() float64 {
+	return w.Relevance + w.Price + w.Delivery + w.Rating + w.Seller + w.Sales + w.Discount + w.Popularity
+}
+
+// DefaultRankWeights returns the weights of the built-in profiles.
+func DefaultRankWeights() map[string]RankWeights {
+	return map[string]RankWeights{
+		RankBestMatch: {Relevance: 0.4, Rating: 0.25, Seller: 0.15, Sales: 0.2},
+		RankCheapest:  {Price: 0.7, Rating: 0.15, Sales: 0.15},
+		RankFastest:   {Delivery: 0.7, Rating: 0.15, Price: 0.15},
+		RankBestRated: {Rating: 0.7, Sales: 0.2, Seller: 0.1},
+		RankMostSold:  {Sales: 0.8, Rating: 0.2},
+		RankBestDeal:  {Discount: 0.5, Price: 0.35, Rating: 0.15},
+		RankPopular:   {Popularity: 0.6, Rating: 0.2, Sales: 0.2},
+	}
+}
+
+// WeightedRanker scores products as a weighted average of normalized signals.
+type WeightedRanker struct {
+	Profile string
+	Weights RankWeights
+}
+
+// NewRankers builds the built-in profiles, replacing the weights of any
+// profile present in overrides. Overrides with all-zero weights are ignored.
+func NewRankers(overrides map[string]RankWeights) map[string]Ranker {
+	weights := DefaultRankWeights()
+	for name, w := range overrides {
+		if _, ok := weights[name]; ok && w.sum() > 0 {
+			weights[name] = w
+		}
+	}
+	rankers := make(map[string]Ranker, len(weights))
+	for name, w := range weights {
+		rankers[name] = &WeightedRanker{Profile: name, Weights: w}
+	}
+	return rankers
+}
+
+// Rank implements Ranker. Ties keep the upstream order.
+func (r *WeightedRanker) Rank(products []*domain.Product) {
+	if len(products) == 0 {
+		return
+	}
+	signals := rankSignals(products)
+	total := r.Weights.sum()
+	scores := make(map[*domain.Product]float64, len(products))
+	for i, p := range products {
+		s := signals[i]
+		breakdown := &domain.ScoreBreakdown{Profile: r.Profile, Components: map[string]float64{}}
+		add := func(name string, weight, value float64) {
+			if weight == 0 {
+				return
+			}
+			breakdown.Components[name] = roundCents(value)
+			breakdown.Total += weight * value
+		}
+		add(signalRelevance, r.Weights.Relevance, s.relevance)
+		add(signalPrice, r.Weights.Price, s.price)
+		add(signalDelivery, r.Weights.Delivery, s.delivery)
+		add(signalRating, r.Weights.Rating, s.rating)
+		add(signalSeller, r.Weights.Seller, s.seller)
+		add(signalSales, r.Weights.Sales, s.sales)
+		add(signalDiscount, r.Weights.Discount, s.discount)
+		add(signalPopularity, r.Weights.Popularity, s.popularity)
+		if total > 0 {
+			breakdown.Total /= total
+		}
+		breakdown.Total = roundCents(breakdown.Total)
+		p.Score = breakdown
+		scores[p] = breakdown.Total
+	}
+	sort.SliceStable(products, func(i, j int) bool {
+		return scores[products[i]] > scores[products[j]]
+	})
+}
+
+type productSignals struct {
+	relevance, price, delivery, rating, seller, sales, discount, popularity float64
+}
+
+// rankSignals normalizes every signal to 0..100 relative to the candidate set.
+// Relevance is the upstream position, since AliExpress already returns results
+// in keyword-relevance order.
+func rankSignals(products []*domain.Product) []productSignals {
+	n := len(products)
+	minPrice, minDays, maxSold, maxDiscount, maxViews := math.MaxFloat64, math.MaxFloat64, 0, 0.0, 0
+	for _, p := range products {
+		maxDiscount = math.Max(maxDiscount, discountPercent(p))
+		if v := landedPriceUSD(p); v > 0 && v < minPrice {
+			minPrice = v
+		}
+		if d, ok := deliveryDays(p); ok && d < minDays {
+			minDays = d
+		}
+		if p.NumberSold > maxSold {
+			maxSold = p.NumberSold
+		}
+		maxViews = max(maxViews, p.RecentViews)
+	}
+
+	out := make([]productSignals, n)
+	for i, p := range products {
+		s := &out[i]
+		s.relevance = 100 * (1 - float64(i)/float64(n))
+		if v := landedPriceUSD(p); v > 0 {
+			s.price = 100 * minPrice / v
+		}
+		if d, ok := deliveryDays(p); ok {
+			s.delivery = 100 * math.Max(minDays, 1) / math.Max(d, 1)
+		}
+		s.rating = ratingScore(p.ProductRating)
+		s.seller = math.Max(0, math.Min(100, float64(p.SellerScore)))
+		if maxSold > 0 && p.NumberSold > 0 {
+			s.sales = 100 * math.Log1p(float64(p.NumberSold)) / math.Log1p(float64(maxSold))
+		}
+		if maxDiscount > 0 {
+			s.discount = 100 * discountPercent(p) / maxDiscount
+		}
+		if maxViews > 0 && p.RecentViews > 0 {
+			s.popularity = 100 * math.Log1p(float64(p.RecentViews)) / math.Log1p(float64(maxViews))
+		}
+	}
+	return out
+}
+
+// landedPriceUSD is the price compared by the cheapest profile: the landed
+// cost when it has been computed, otherwise the sticker price.
+func landedPriceUSD(p *domain.Product) float64 {
+	if p.LandedCost != nil && p.LandedCost.TotalUSD > 0 {
+		return p.LandedCost.TotalUSD
+	}
+	return p.Price.USD
+}
+
+// discountPercent is the markdown from the original price, falling back to
+// the discount the source reported.
+func discountPercent(p *domain.Product) float64 {
+	if o := p.OriginalPrice; o != nil && o.USD > 0 && p.Price.USD > 0 && p.Price.USD < o.USD {
+		return 100 * (o.USD - p.Price.USD) / o.USD
+	}
+	return math.Max(0, math.Min(100, p.Discount))
+}
+
+// ratingScore maps a rating to 0..100. AliExpress reports a positive-feedback
+// percentage while other sources use a 0..5 star scale.
+func ratingScore(r float64) float64 {
+	switch {
+	case r <= 0:
+		return 0
+	case r <= 5:
+		return r / 5 * 100
+	case r <= 100:
+		return r
+	}
+	return 100
+}
+
+// deliveryDays returns the upper bound of a product's delivery estimate.
+func deliveryDays(p *domain.Product) (float64, bool) {
+	if p.DeliveryMaxDays > 0 {
+		return float64(p.DeliveryMaxDays), true
+	}
+	if _, hi, ok := ParseDeliveryEstimate(p.DeliveryEstimate); ok {
+		return float64(hi), true
+	}
+	return 0, false
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+func rankingFixture() []*domain.Product {
+	return []*domain.Product{
+		{ID: "relevant", Price: domain.Price{USD: 30}, ProductRating: 90, DeliveryEstimate: "20-30", NumberSold: 50},
+		{ID: "cheap", Price: domain.Price{USD: 10}, ProductRating: 80, DeliveryEstimate: "25-40", NumberSold: 10},
+		{ID: "fast", Price: domain.Price{USD: 25}, ProductRating: 85, DeliveryEstimate: "3-5 days", NumberSold: 20},
+		{ID: "popular", Price: domain.Price{USD: 28}, ProductRating: 99, DeliveryEstimate: "15-25", NumberSold: 5000},
+	}
+}
+
+func TestRankers_ProfilesOrderBySignal(t *testing.T) {
+	rankers := NewRankers(nil)
+	cases := map[string]string{
+		RankCheapest:  "cheap",
+		RankFastest:   "fast",
+		RankBestRated: "popular",
+		RankMostSold:  "popular",
+	}
+	for profile, want := range cases {
+		products := rankingFixture()
+		rankers[profile].Rank(products)
+		if products[0].ID != want {
+			t.Errorf("%s: expected %s first, got %s", profile, want, products[0].ID)
+		}
+		for _, p := range products {
+			if p.Score == nil || p.Score.Profile != profile {
+				t.Fatalf("%s: missing score breakdown on %s: %+v", profile, p.ID, p.Score)
+			}
+		}
+		for i := 1; i < len(products); i++ {
+			if products[i-1].Score.Total < products[i].Score.Total {
+				t.Errorf("%s: not sorted by total at %d", profile, i)
+			}
+		}
+	}
+}
+
+func TestRankers_BreakdownOnlyListsWeightedSignals(t *testing.T) {
+	products := rankingFixture()
+	NewRankers(nil)[RankMostSold].Rank(products)
+
+	c := products[0].Score.Components
+	if len(c) != 2 || c[signalSales] != 100 || c[signalRating] != 99 {
+		t.Errorf("unexpected components: %+v", c)
+	}
+	if got := products[0].Score.Total; got != 99.8 {
+		t.Errorf("expected total 99.8, got %v", got)
+	}
+}
+
+func TestNewRankers_OverridesWeights(t *testing.T) {
+	rankers := NewRankers(map[string]RankWeights{
+		RankBestMatch: {Price: 1},
+		"unknown":     {Price: 1},
+		RankFastest:   {},
+	})
+	if _, ok := rankers["unknown"]; ok {
+		t.Error("unknown profiles must not be added")
+	}
+	if w := rankers[RankFastest].(*WeightedRanker).Weights; w != DefaultRankWeights()[RankFastest] {
+		t.Errorf("all-zero override should be ignored, got %+v", w)
+	}
+
+	products := rankingFixture()
+	rankers[RankBestMatch].Rank(products)
+	if products[0].ID != "cheap" {
+		t.Errorf("overridden best_match should rank by price, got %s first", products[0].ID)
+	}
+}
+
+func TestSearch_RanksWithSelectedProfile(t *testing.T) {
+	lg := &fakeLLMGateway{}
+
+	uc := NewSearchProductsUseCase(&fakeAlibabaGateway{products: rankingFixture()}, lg, nil, nil)
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone", Filters: domain.SearchFilters{Sort: RankFastest}})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if res.RankingProfile != RankFastest || res.Products[0].ID != "fast" {
+		t.Errorf("expected fastest ranking, got %q with %s first", res.RankingProfile, res.Products[0].ID)
+	}
+	if res.Products[0].Score == nil {
+		t.Error("score breakdown should survive summarization")
+	}
+
+	uc = NewSearchProductsUseCase(&fakeAlibabaGateway{products: rankingFixture()}, lg, nil, nil)
+	res, err = uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone", Filters: domain.SearchFilters{Sort: "price_desc"}})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if res.RankingProfile != "" || res.Products[0].ID != "relevant" || res.Products[0].Score != nil {
+		t.Errorf("price_desc should keep upstream order unscored, got %q with %s first", res.RankingProfile, res.Products[0].ID)
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// Sort options accepted from clients, mapped to the local ranking profile.
+// An empty profile keeps whatever order the gateway returned. The older names
+// are kept as aliases of the matching ranking profiles.
+var sortOptions = map[string]string{
+	RankBestMatch: RankBestMatch,
+	RankCheapest:  RankCheapest,
+	RankFastest:   RankFastest,
+	RankBestRated: RankBestRated,
+	RankMostSold:  RankMostSold,
+	RankPopular:   RankPopular,
+
+	"relevance":   RankBestMatch,
+	"price_asc":   RankCheapest,
+	"price_desc":  "",
+	"orders_desc": RankMostSold,
+}
+
+// rankingProfile returns the ranking profile for a client sort value,
+// defaulting to best match when none was given.
+func rankingProfile(sort string) string {
+	if sort == "" {
+		return RankBestMatch
+	}
+	return sortOptions[sort]
+}
+
+// IsValidSort reports whether s is a supported sort option.
+func IsValidSort(s string) bool {
+	_, ok := sortOptions[s]
+	return ok
+}
+
+// IsValidCurrency reports whether c is a currency clients may express prices in.
+func IsValidCurrency(c string) bool {
+	return c == "USD" || c == "ETB"
+}
+
+// DefaultFilterCurrency is the currency of explicit price filters sent
+// without one. It is fixed, so an explicit bound means the same amount
+// whichever currency the query names.
+const DefaultFilterCurrency = "USD"
+
+// applyExplicitFilters overlays client-supplied filters on the LLM-derived
+// intent so that explicit values always win. Explicit price bounds are in
+// f.Currency, or DefaultFilterCurrency; f.Currency alone changes nothing. A
+// parsed bound left in place in another currency is dropped rather than
+// reread in the explicit one, and reported.
+func applyExplicitFilters(intent *domain.SearchIntent, f domain.SearchFilters) []domain.IntentFieldError {
+	var warnings []domain.IntentFieldError
+	if f.MinPrice != nil || f.MaxPrice != nil {
+		currency := strings.ToUpper(f.Currency)
+		if currency == "" {
+			currency = DefaultFilterCurrency
+		}
+		// Normalize reads a parsed bound without a currency as ETB.
+		parsed := strings.ToUpper(strings.TrimSpace(intent.Currency))
+		if parsed == "" {
+			parsed = "ETB"
+		}
+		if parsed != currency {
+			if f.MinPrice == nil && intent.MinPrice != nil {
+				warnings = append(warnings, domain.IntentFieldError{Field: "minPrice", Message: "parsed in " + parsed + ", not the explicit " + currency + "; dropped"})
+				intent.MinPrice = nil
+			}
+			if f.MaxPrice == nil && intent.MaxPrice != nil {
+				warnings = append(warnings, domain.IntentFieldError{Field: "maxPrice", Message: "parsed in " + parsed + ", not the explicit " + currency + "; dropped"})
+				intent.MaxPrice = nil
+			}
+		}
+		if f.MinPrice != nil {
+			v := *f.MinPrice
+			intent.MinPrice = &v
+		}
+		if f.MaxPrice != nil {
+			v := *f.MaxPrice
+			intent.MaxPrice = &v
+		}
+		intent.Currency = currency
+	}
+	if f.MaxDeliveryDays != nil {
+		v := *f.MaxDeliveryDays
+		intent.MaxDeliveryDays = &v
+	}
+	if f.CategoryID != "" {
+		intent.CategoryID = f.CategoryID
+	}
+	if f.Sort != "" {
+		intent.Sort = f.Sort
+	}
+	return warnings
+}
+
+// effectiveFilters reads the merged intent back into client terms.
+func effectiveFilters(intent domain.SearchIntent) domain.SearchFilters {
+	out := domain.SearchFilters{
+		MinPrice:        intent.MinPrice,
+		MaxPrice:        intent.MaxPrice,
+		MaxDeliveryDays: intent.MaxDeliveryDays,
+		CategoryID:      intent.CategoryID,
+		Sort:            intent.Sort,
+	}
+	if out.MinPrice != nil || out.MaxPrice != nil {
+		out.Currency = intent.Currency
+	}
+	return out
+}
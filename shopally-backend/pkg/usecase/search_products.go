@@ -2,11 +2,41 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"log"
-	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/shopally-ai/internal/contextkeys"
 	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
+)
+
+const (
+	// DefaultSearchCacheTTL is how long a cached search result is served as fresh.
+	DefaultSearchCacheTTL = 10 * time.Minute
+	// DefaultSearchStaleTTL is how long past its fresh window a result may still be
+	// served while a background refresh runs.
+	DefaultSearchStaleTTL = 30 * time.Minute
+	// DefaultSearchFallbackTTL is how long past its stale window a result is
+	// kept to be served, marked stale, while the product source is unavailable.
+	DefaultSearchFallbackTTL = 24 * time.Hour
+
+	// DefaultSummaryConcurrency caps simultaneous per-product LLM calls.
+	DefaultSummaryConcurrency = 5
+	// DefaultSummaryTimeout bounds a single product summarization.
+	DefaultSummaryTimeout = 8 * time.Second
+	// DefaultSummaryBudget bounds summarization of a whole page.
+	DefaultSummaryBudget = 12 * time.Second
+
+	searchRefreshTimeout = 60 * time.Second
+
+	searchCacheKeyPrefix = "search:"
 )
 
 // SearchProductsUseCase contains the business logic for searching products.
@@ -15,108 +45,427 @@ type SearchProductsUseCase struct {
 	alibabaGateway domain.AlibabaGateway
 	llmGateway     domain.LLMGateway
 	cacheGateway   domain.CacheGateway
+	fxClient       domain.IFXClient
+
+	// CacheTTL, StaleTTL and FallbackTTL control the result cache; they are
+	// ignored when no cache gateway is configured.
+	CacheTTL    time.Duration
+	StaleTTL    time.Duration
+	FallbackTTL time.Duration
+
+	// SummaryConcurrency, SummaryTimeout and SummaryBudget bound the per-product
+	// LLM enhancement step; see summarizeProducts.
+	SummaryConcurrency int
+	SummaryTimeout     time.Duration
+	SummaryBudget      time.Duration
+
+	// Rankers holds the ranking profiles selectable with the sort filter.
+	Rankers map[string]Ranker
+
+	// CandidateTarget is how many upstream products are fetched, deduplicated
+	// and ranked per query; every page is cut from that pool, so results past
+	// it are not reachable. The pool is fetched in pages of MaxPageSize.
+	CandidateTarget int
+	// FetchConcurrency and FetchInterval bound the page fetches beyond the
+	// first; FetchInterval is shared by all searches.
+	FetchConcurrency int
+	FetchInterval    time.Duration
+
+	// BackfillPages caps extra upstream pages fetched when local price and
+	// delivery enforcement leaves fewer matches than one page needs.
+	BackfillPages int
+
+	// Policy, if set, drops fetched products whose title or category is blocked.
+	Policy *ContentPolicy
+
+	// Categories, if set, resolves category names in the parsed intent to
+	// category IDs and drops IDs it does not know.
+	Categories *CategoryCatalog
+
+	// LandedCost, if set, attaches the Ethiopian landed cost to every result;
+	// the cheapest ranking profile then compares landed prices.
+	LandedCost *LandedCostCalculator
+
+	// Affiliate, if set, replaces deeplinks with tracked affiliate redirects.
+	Affiliate *AffiliateLinker
+
+	// Views, if set, attaches recent view counts for the popularity signal.
+	Views *ProductViewsUseCase
+
+	// Prices, if set, records every fetched product and its price in the
+	// background.
+	Prices *PriceTracker
+
+	// SessionTTL is how long a conversational session is kept in the cache
+	// after its last search.
+	SessionTTL time.Duration
+
+	refreshing sync.Map // cache key -> struct{}, guards background refreshes
+	inflight   util.CallGroup[*domain.SearchResult]
+
+	fetchLimiter pageLimiter
 }
 
 // NewSearchProductsUseCase creates a new SearchProductsUseCase.
-func NewSearchProductsUseCase(ag domain.AlibabaGateway, lg domain.LLMGateway, cg domain.CacheGateway) *SearchProductsUseCase {
+func NewSearchProductsUseCase(ag domain.AlibabaGateway, lg domain.LLMGateway, cg domain.CacheGateway, fx domain.IFXClient) *SearchProductsUseCase {
 	return &SearchProductsUseCase{
 		alibabaGateway: ag,
 		llmGateway:     lg,
 		cacheGateway:   cg,
+		fxClient:       fx,
+		CacheTTL:       DefaultSearchCacheTTL,
+		StaleTTL:       DefaultSearchStaleTTL,
+		FallbackTTL:    DefaultSearchFallbackTTL,
+
+		SummaryConcurrency: DefaultSummaryConcurrency,
+		SummaryTimeout:     DefaultSummaryTimeout,
+		SummaryBudget:      DefaultSummaryBudget,
+
+		Rankers:          NewRankers(nil),
+		CandidateTarget:  DefaultCandidateTarget,
+		FetchConcurrency: DefaultFetchConcurrency,
+		FetchInterval:    DefaultFetchInterval,
+		BackfillPages:    DefaultBackfillPages,
+		SessionTTL:       DefaultSessionTTL,
+		LandedCost:       NewLandedCostCalculator(DefaultLandedCostSettings()),
 	}
 }
 
-// Search runs the mocked search pipeline: Parse -> Fetch (using intent as filters).
-func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (interface{}, error) {
-	// Parse intent via LLM
-	intent, err := uc.llmGateway.ParseIntent(ctx, query)
+// cachedSearch is the envelope stored in the cache for a search result.
+type cachedSearch struct {
+	Result     *domain.SearchResult `json:"result"`
+	FreshUntil time.Time            `json:"freshUntil"`
+}
+
+// Search serves results from the cache when possible and otherwise runs the
+// pipeline. Stale entries are returned immediately while a single background
+// refresh repopulates the cache. Older entries are only served, marked
+// Stale, when the product source is unavailable. Concurrent identical
+// searches share a single pipeline run.
+func (uc *SearchProductsUseCase) Search(ctx context.Context, req domain.SearchRequest) (*domain.SearchResult, error) {
+	req = normalizeSearchRequest(req)
+
+	// Follow-ups depend on session state and bypass the result cache.
+	if sess := uc.loadSession(ctx, req.SessionID); sess != nil {
+		result, err := uc.runPipeline(ctx, req, sess, nil)
+		if err != nil {
+			return nil, err
+		}
+		return uc.saveSession(ctx, sess, result), nil
+	}
+
+	key := searchCacheKey(ctx, req)
+	if uc.cacheGateway == nil {
+		return uc.runShared(ctx, key, req)
+	}
+
+	entry, cached := uc.readCache(ctx, key)
+	if cached {
+		now := time.Now()
+		if now.Before(entry.FreshUntil) {
+			log.Println("SearchProductsUseCase: cache hit for query:", req.Query)
+			return entry.Result, nil
+		}
+		if now.Before(entry.FreshUntil.Add(uc.StaleTTL)) {
+			log.Println("SearchProductsUseCase: serving stale result and refreshing for query:", req.Query)
+			uc.refreshInBackground(ctx, key, req)
+			return entry.Result, nil
+		}
+	}
+
+	result, err := uc.runShared(ctx, key, req)
 	if err != nil {
-		// For V1 mock, fail soft by using empty filters
-		log.Println("SearchProductsUseCase: LLM intent parsing failed for query:", query, "error:", err)
-		intent = map[string]interface{}{}
+		if cached && errors.Is(err, domain.ErrUpstreamUnavailable) {
+			log.Println("SearchProductsUseCase: product source unavailable, serving last cached result for query:", req.Query, "error:", err)
+			stale := *entry.Result
+			stale.Stale = true
+			return &stale, nil
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// runShared runs the pipeline once for all concurrent searches with the same
+// cache key and caches the result. A caller that gives up does not cancel the
+// pipeline for the others.
+func (uc *SearchProductsUseCase) runShared(ctx context.Context, key string, req domain.SearchRequest) (*domain.SearchResult, error) {
+	result, shared, err := uc.inflight.Do(ctx, key, func(ctx context.Context) (*domain.SearchResult, error) {
+		result, err := uc.runPipeline(ctx, req, nil, nil)
+		if err == nil && uc.cacheGateway != nil {
+			uc.writeCache(ctx, key, result)
+		}
+		return result, err
+	})
+	if shared {
+		log.Println("SearchProductsUseCase: shared in-flight pipeline for query:", req.Query)
 	}
+	return result, err
+}
 
-	log.Println("SearchProductsUseCase: parsed intent for query:", query, "as", intent)
+// normalizeSearchRequest applies the default and maximum page size. Every
+// page past the end of the pool is the same empty page, so the page is capped
+// at the first of them; this also keeps the page arithmetic from overflowing.
+func normalizeSearchRequest(req domain.SearchRequest) domain.SearchRequest {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = DefaultPageSize
+	}
+	if req.PageSize > MaxPageSize {
+		req.PageSize = MaxPageSize
+	}
+	req.Page = min(req.Page, LastPage(req.PageSize)+1)
+	return req
+}
 
-	// Prune empty filters (nil/empty string) before passing to gateway
-	filters := make(map[string]interface{})
-	for k, v := range intent {
-		switch vv := v.(type) {
-		case nil:
-			continue
-		case string:
-			if vv == "" {
-				continue
-			}
+func (uc *SearchProductsUseCase) readCache(ctx context.Context, key string) (*cachedSearch, bool) {
+	raw, err := uc.cacheGateway.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			log.Println("SearchProductsUseCase: cache read failed for key:", key, "error:", err)
 		}
-		filters[k] = v
+		return nil, false
+	}
+	var entry cachedSearch
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil || entry.Result == nil {
+		log.Println("SearchProductsUseCase: discarding unreadable cache entry for key:", key)
+		return nil, false
 	}
-	log.Println("SearchProductsUseCase: using filters for query:", query, "as", filters)
+	return &entry, true
+}
 
-	var keywords string
+// writeCache caches result and sets its SessionID to key, from which a
+// follow-up starts a session; see loadSession.
+func (uc *SearchProductsUseCase) writeCache(ctx context.Context, key string, result *domain.SearchResult) {
+	result.SessionID = key
+	entry := cachedSearch{Result: result, FreshUntil: time.Now().Add(uc.CacheTTL)}
+	if err := uc.cacheGateway.Set(ctx, key, entry, uc.CacheTTL+uc.StaleTTL+uc.FallbackTTL); err != nil {
+		log.Println("SearchProductsUseCase: cache write failed for key:", key, "error:", err)
+		result.SessionID = ""
+	}
+}
 
-	if keywordsStr, ok := filters["keywords"].(string); ok && keywordsStr != "" {
-		keywords = keywordsStr
-	} else {
-		keywords = query
+// refreshInBackground re-runs the pipeline for a stale key unless a refresh for
+// it is already in flight. The request-scoped values (language, currency) are
+// kept but the caller's cancellation is not.
+func (uc *SearchProductsUseCase) refreshInBackground(ctx context.Context, key string, req domain.SearchRequest) {
+	if _, busy := uc.refreshing.LoadOrStore(key, struct{}{}); busy {
+		return
 	}
+	bctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), searchRefreshTimeout)
+	go func() {
+		defer cancel()
+		defer uc.refreshing.Delete(key)
+		result, err := uc.runPipeline(bctx, req, nil, nil)
+		if err != nil {
+			log.Println("SearchProductsUseCase: background refresh failed for query:", req.Query, "error:", err)
+			return
+		}
+		uc.writeCache(bctx, key, result)
+	}()
+}
+
+// searchCacheKey builds a key from the normalized query, the requested page,
+// the explicit filters and the response language and currency carried on the context.
+func searchCacheKey(ctx context.Context, req domain.SearchRequest) string {
+	lang, _ := ctx.Value(contextkeys.RespLang).(string)
+	currency, _ := ctx.Value(contextkeys.RespCurrency).(string)
+
+	parts := []string{
+		normalizeQuery(req.Query),
+		strings.ToLower(lang),
+		strings.ToUpper(currency),
+		strconv.Itoa(req.Page),
+		strconv.Itoa(req.PageSize),
+	}
+	if explicit, err := json.Marshal(req.Filters); err == nil {
+		parts = append(parts, string(explicit))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return searchCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func normalizeQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// runPipeline runs the search pipeline: Parse -> Fetch (using intent as filters) -> Enforce -> Rank -> Price -> Summarize.
+// emit, if non-nil, is notified after each stage and must be safe for concurrent use.
+// sess, if non-nil, is the conversational session being refined; results it
+// has already shown are excluded.
+func (uc *SearchProductsUseCase) runPipeline(ctx context.Context, req domain.SearchRequest, sess *domain.SearchSession, emit SearchObserver) (*domain.SearchResult, error) {
+	query := req.Query
+	if emit == nil {
+		emit = func(string, interface{}) {}
+	}
+
+	// Parse intent via LLM, refining the session's previous intent on follow-ups
+	intent := uc.resolveIntent(ctx, query, sess)
+
+	log.Printf("SearchProductsUseCase: parsed intent for query: %s as %+v", query, intent)
 
-	// Fetch products from the gateway
-	products, err := uc.alibabaGateway.FetchProducts(ctx, keywords, filters)
+	// Only the parsed intent is resolved; explicit client category IDs are trusted.
+	var warnings []domain.IntentFieldError
+	if uc.Categories != nil {
+		warnings = uc.Categories.ResolveIntent(&intent)
+	}
+
+	warnings = append(warnings, applyExplicitFilters(&intent, req.Filters)...)
+	if intent.Keywords == "" {
+		intent.Keywords = query
+	}
+	warnings = append(warnings, intent.Normalize()...)
+	if intent.Sort != "" && !IsValidSort(intent.Sort) {
+		warnings = append(warnings, domain.IntentFieldError{Field: "sort", Message: "unsupported sort " + intent.Sort + "; dropped"})
+		intent.Sort = ""
+	}
+	for _, w := range warnings {
+		log.Println("SearchProductsUseCase: corrected intent for query:", query, "-", w.Error())
+	}
+
+	// Echo filters in the client's currency, before budgets are converted to USD
+	effective := effectiveFilters(intent)
+	resolved := intent
+
+	quote, fxOK := etbQuote(ctx, uc.fxClient)
+	budgetRate := normalizeBudgetCurrency(&intent, quote, fxOK)
 
-	log.Println("SearchProductsUseCase: fetched", len(products), "products for query:", query, "with filters:", filters)
+	log.Printf("SearchProductsUseCase: using intent for query: %s as %+v", query, intent)
+	keywords := intent.Keywords
+	emit(SearchEventIntent, IntentEvent{Keywords: keywords, Filters: effective})
+
+	// Fetch a pool of candidates from the gateway, then enforce price and
+	// delivery constraints locally since upstream does not reliably honour them.
+	// The first page tells how many upstream results there are.
+	first, err := uc.alibabaGateway.FetchProducts(ctx, intent, 1, MaxPageSize)
 	if err != nil {
 		return nil, err
 	}
+	total := first.TotalRecordCount
+	upstreamPages := (total + MaxPageSize - 1) / MaxPageSize
 
-	// Default ranking if filters are sparse (no price or delivery constraints)
-	if _, ok1 := filters["min_price"]; !ok1 {
-		if _, ok2 := filters["max_price"]; !ok2 {
-			if _, ok3 := filters["delivery_days_max"]; !ok3 {
-				sort.SliceStable(products, func(i, j int) bool {
-					si := defaultScore(products[i])
-					sj := defaultScore(products[j])
-					return si > sj
-				})
+	constraints := constraintsFromIntent(intent)
+	seen := make(map[string]bool)
+	alreadyShown := make(map[string]bool)
+	if sess != nil {
+		for _, id := range sess.SeenIDs {
+			alreadyShown[id] = true
+		}
+	}
+	var matching, demoted []*domain.Product
+	dropped, excluded := 0, 0
+	collect := func(batch []*domain.Product) {
+		var fresh []*domain.Product
+		for _, p := range batch {
+			if alreadyShown[p.ID] {
+				excluded++
+				continue
+			}
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				fresh = append(fresh, p)
 			}
 		}
+		if uc.Policy != nil {
+			var blocked int
+			fresh, blocked = uc.Policy.FilterProducts(fresh)
+			dropped += blocked
+		}
+		if uc.Prices != nil {
+			uc.Prices.RecordInBackground(ctx, fresh, quote, fxOK)
+		}
+		annotateDelivery(fresh)
+		m, d, n := constraints.split(fresh)
+		matching, demoted, dropped = append(matching, m...), append(demoted, d...), dropped+n
+	}
+	collect(first.Products)
+
+	lastPage := 1
+	if len(first.Products) > 0 {
+		lastPage = min(uc.candidatePages(), max(upstreamPages, 1))
+		for _, page := range uc.fetchPages(ctx, intent, 2, lastPage) {
+			if page != nil {
+				collect(page.Products)
+			}
+		}
+	}
+
+	// Backfill from the following upstream pages while too few results match
+	// to fill a page. This depends only on the page size, which cursors keep,
+	// so the pool stays the same across the pages of a query.
+	for extra := 0; extra < uc.BackfillPages && dropped+excluded+len(demoted) > 0 && len(matching) < req.PageSize; extra++ {
+		if lastPage >= upstreamPages || lastPage >= maxCandidatePages {
+			break
+		}
+		more := uc.fetchPages(ctx, intent, lastPage+1, lastPage+1)[0]
+		if more == nil || len(more.Products) == 0 {
+			break
+		}
+		lastPage++
+		collect(more.Products)
+	}
+	log.Println("SearchProductsUseCase: fetched", len(seen), "candidates from", lastPage, "pages for query:", query)
+	if dropped > 0 {
+		log.Println("SearchProductsUseCase: dropped", dropped, "products violating constraints or content policy for query:", query)
+	}
+
+	if uc.LandedCost != nil {
+		uc.LandedCost.Apply(matching, quote, fxOK)
+		uc.LandedCost.Apply(demoted, quote, fxOK)
+	}
+	if uc.Views != nil {
+		uc.Views.AnnotatePopularity(ctx, append(append([]*domain.Product(nil), matching...), demoted...))
+	}
+
+	// Rank matches and demoted results separately so demoted ones stay last,
+	// then cut the requested page from the ranked pool.
+	profile := rankingProfile(intent.Sort)
+	if ranker, ok := uc.Rankers[profile]; ok {
+		ranker.Rank(matching)
+		ranker.Rank(demoted)
+	} else {
+		profile = ""
+	}
+	ranked := append(matching, demoted...)
+	products := []*domain.Product{}
+	if start := (req.Page - 1) * req.PageSize; start < len(ranked) {
+		products = ranked[start:min(start+req.PageSize, len(ranked))]
 	}
 
 	log.Println("SearchProductsUseCase: ranked products for query:", query)
 
-	// Parallel summarization: each product summary is independent.
+	if fxOK {
+		applyETBPrices(products, quote)
+	}
+	if uc.Affiliate != nil {
+		uc.Affiliate.Apply(ctx, products, domain.ClickSurfaceSearch)
+	}
+
+	pageInfo := domain.PageInfo{
+		TotalRecordCount: total,
+		CurrentPageNo:    req.Page,
+		PageSize:         req.PageSize,
+		NextCursor:       nextPageCursor(req.Page, req.PageSize, len(ranked)),
+	}
+	emit(SearchEventProducts, ProductsEvent{Products: append([]*domain.Product(nil), products...), Page: pageInfo, FXDegraded: !fxOK})
+
 	// Parallel summarization: each product summary is independent.
 	if uc.llmGateway != nil {
-		var wg sync.WaitGroup
-		wg.Add(len(products))
-
-		for i := range products {
-			go func(index int) {
-				defer wg.Done()
-				if products[index] == nil {
-					return
-				}
-
-				// Get the original product and user prompt from context if available
-				userPrompt := query
-
-				// Get enhanced product with all details
-				enhancedProduct, err := uc.llmGateway.SummarizeProduct(ctx, products[index], userPrompt)
-				if err == nil && enhancedProduct != nil {
-					// Replace the entire product with enhanced version
-					products[index] = enhancedProduct
-				}
-			}(i)
-		}
-		wg.Wait()
+		uc.summarizeProducts(ctx, products, query, emit)
 	}
 
-	// Return the envelope-compatible data payload
-	return map[string]interface{}{"products": products}, nil
-}
+	return &domain.SearchResult{
+		Products:     products,
+		Page:         pageInfo,
+		Filters:      effective,
+		FXDegraded:   !fxOK,
+		BudgetFXRate: budgetRate,
 
-func defaultScore(p *domain.Product) float64 {
-	// 0..5 rating scaled to 0..100, seller score is already 0..100
-	// Weighted blend: 0.6 rating + 0.4 seller
-	return 0.6*(p.ProductRating/5.0*100.0) + 0.4*float64(p.SellerScore)
+		RankingProfile: profile,
+		FilteredOut:    dropped,
+		IntentWarnings: warnings,
+		Intent:         &resolved,
+	}, nil
 }
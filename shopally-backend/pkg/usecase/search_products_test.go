@@ -0,0 +1,855 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeAlibabaGateway struct {
+	calls    int32
+	products []*domain.Product
+	total    int
+	// pages, when set, serves products per page_no instead of products.
+	pages map[int][]*domain.Product
+	// err, when set, fails FetchProducts.
+	err error
+	// block, when set, makes FetchProducts wait until it is closed or ctx is done.
+	block chan struct{}
+
+	categories []domain.Category
+
+	// linkErr, when set, fails GenerateAffiliateLinks.
+	linkCalls   int32
+	linkErr     error
+	linkSources []string
+
+	mu                 sync.Mutex
+	lastIntent         domain.SearchIntent
+	lastPage, lastSize int
+	lastDeals          domain.DealsFilters
+}
+
+func (f *fakeAlibabaGateway) FetchProducts(ctx context.Context, intent domain.SearchIntent, pageNo, pageSize int) (*domain.ProductPage, error) {
+	atomic.AddInt32(&f.calls, 1)
+	f.mu.Lock()
+	f.lastIntent, f.lastPage, f.lastSize = intent, pageNo, pageSize
+	f.mu.Unlock()
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	src := f.products
+	if f.pages != nil {
+		src = f.pages[pageNo]
+	}
+	out := make([]*domain.Product, 0, len(src))
+	for _, p := range src {
+		cp := *p
+		out = append(out, &cp)
+	}
+	total := f.total
+	if total == 0 {
+		total = len(out)
+	}
+	return &domain.ProductPage{Products: out, TotalRecordCount: total, CurrentPageNo: pageNo}, nil
+}
+
+func (f *fakeAlibabaGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	atomic.AddInt32(&f.calls, 1)
+	for _, p := range f.products {
+		if p.ID == productID {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrProductNotFound
+}
+
+func (f *fakeAlibabaGateway) FetchHotProducts(ctx context.Context, filters domain.DealsFilters, pageNo, pageSize int) (*domain.ProductPage, error) {
+	f.mu.Lock()
+	f.lastDeals = filters
+	f.mu.Unlock()
+	return f.FetchProducts(ctx, domain.SearchIntent{}, pageNo, pageSize)
+}
+
+func (f *fakeAlibabaGateway) FetchCategories(ctx context.Context) ([]domain.Category, error) {
+	return f.categories, nil
+}
+
+func (f *fakeAlibabaGateway) GenerateAffiliateLinks(ctx context.Context, sourceURLs []string) (map[string]string, error) {
+	atomic.AddInt32(&f.linkCalls, 1)
+	f.mu.Lock()
+	f.linkSources = append(f.linkSources, sourceURLs...)
+	f.mu.Unlock()
+	if f.linkErr != nil {
+		return nil, f.linkErr
+	}
+	links := make(map[string]string, len(sourceURLs))
+	for _, src := range sourceURLs {
+		links[src] = src + "?aff=1"
+	}
+	return links, nil
+}
+
+type fakeLLMGateway struct {
+	intent domain.SearchIntent
+	delta  domain.IntentDelta
+
+	mu       sync.Mutex
+	lastSeen []string
+
+	// delay makes SummarizeProduct block until it elapses or ctx is done.
+	delay            time.Duration
+	inFlight, maxObs int32
+}
+
+func (f *fakeLLMGateway) ParseIntent(ctx context.Context, query string) (*domain.SearchIntent, error) {
+	out := f.intent
+	return &out, nil
+}
+
+func (f *fakeLLMGateway) RefineIntent(ctx context.Context, previous domain.SearchIntent, seenIDs []string, followUp string) (*domain.IntentDelta, error) {
+	f.mu.Lock()
+	f.lastSeen = append([]string(nil), seenIDs...)
+	f.mu.Unlock()
+	out := f.delta
+	return &out, nil
+}
+
+func (f *fakeLLMGateway) SummarizeProduct(ctx context.Context, p *domain.Product, prompt string) (*domain.Product, error) {
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		old := atomic.LoadInt32(&f.maxObs)
+		if n <= old || atomic.CompareAndSwapInt32(&f.maxObs, old, n) {
+			break
+		}
+	}
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	out := *p
+	out.AIEnriched = true
+	return &out, nil
+}
+
+func (f *fakeLLMGateway) FallbackSummary(ctx context.Context, p *domain.Product, prompt string) *domain.Product {
+	out := *p
+	out.AIEnriched = false
+	return &out
+}
+
+func (f *fakeLLMGateway) CompareProducts(ctx context.Context, products []*domain.Product) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+type memCacheGateway struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemCacheGateway() *memCacheGateway {
+	return &memCacheGateway{data: map[string]string{}}
+}
+
+func (m *memCacheGateway) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	if !ok {
+		return "", domain.ErrCacheMiss
+	}
+	return v, nil
+}
+
+func (m *memCacheGateway) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = string(b)
+	return nil
+}
+
+func searchCtx(lang string) context.Context {
+	ctx := context.WithValue(context.Background(), contextkeys.RespLang, lang)
+	return context.WithValue(ctx, contextkeys.RespCurrency, "USD")
+}
+
+func TestSearch_CachesResults(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1", Title: "Phone"}}}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, newMemCacheGateway(), nil)
+
+	if _, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "Cheap  Phone"}); err != nil {
+		t.Fatalf("first search failed: %v", err)
+	}
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "cheap phone"})
+	if err != nil {
+		t.Fatalf("second search failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 1 {
+		t.Errorf("expected 1 upstream fetch for normalized duplicate query, got %d", got)
+	}
+	if len(res.Products) != 1 || res.Products[0].ID != "1" {
+		t.Errorf("unexpected cached result: %+v", res.Products)
+	}
+
+	if _, err := uc.Search(searchCtx("am"), domain.SearchRequest{Query: "cheap phone"}); err != nil {
+		t.Fatalf("amharic search failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 2 {
+		t.Errorf("expected a separate cache entry per language, got %d fetches", got)
+	}
+}
+
+func TestSearch_ServesStaleAndRefreshes(t *testing.T) {
+	// /search and /search/stream share the stale-while-revalidate path.
+	searches := map[string]func(*SearchProductsUseCase, context.Context, domain.SearchRequest) (*domain.SearchResult, error){
+		"search": (*SearchProductsUseCase).Search,
+		"stream": func(uc *SearchProductsUseCase, ctx context.Context, req domain.SearchRequest) (*domain.SearchResult, error) {
+			return uc.SearchStream(ctx, req, func(string, interface{}) {})
+		},
+	}
+	for name, search := range searches {
+		t.Run(name, func(t *testing.T) {
+			ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}, block: make(chan struct{})}
+			cache := newMemCacheGateway()
+			uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, cache, nil)
+			ctx := searchCtx("en")
+
+			stale := cachedSearch{
+				Result:     &domain.SearchResult{Products: []*domain.Product{{ID: "old"}}},
+				FreshUntil: time.Now().Add(-time.Minute),
+			}
+			req := domain.SearchRequest{Query: "phone", Page: 1, PageSize: DefaultPageSize}
+			key := searchCacheKey(ctx, req)
+			if err := cache.Set(ctx, key, stale, time.Hour); err != nil {
+				t.Fatal(err)
+			}
+
+			// The refresh blocks upstream, so the stale result is served without waiting for it.
+			res, err := search(uc, ctx, req)
+			if err != nil {
+				t.Fatalf("search failed: %v", err)
+			}
+			if res.Products[0].ID != "old" {
+				t.Errorf("expected stale result to be served, got %q", res.Products[0].ID)
+			}
+			close(ag.block)
+
+			deadline := time.Now().Add(2 * time.Second)
+			for time.Now().Before(deadline) {
+				if entry, ok := uc.readCache(ctx, key); ok && entry.Result.Products[0].ID == "1" {
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			t.Fatal("background refresh did not repopulate the cache")
+		})
+	}
+}
+
+func TestSearch_ServesLastResultWhenUpstreamUnavailable(t *testing.T) {
+	ag := &fakeAlibabaGateway{err: fmt.Errorf("circuit open: %w", domain.ErrUpstreamUnavailable)}
+	cache := newMemCacheGateway()
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, cache, nil)
+	ctx := searchCtx("en")
+
+	// Past its stale window, the entry is only kept as a fallback.
+	old := cachedSearch{
+		Result:     &domain.SearchResult{Products: []*domain.Product{{ID: "old"}}},
+		FreshUntil: time.Now().Add(-uc.StaleTTL - time.Minute),
+	}
+	req := domain.SearchRequest{Query: "phone", Page: 1, PageSize: DefaultPageSize}
+	if err := cache.Set(ctx, searchCacheKey(ctx, req), old, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := uc.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if !res.Stale || res.Products[0].ID != "old" {
+		t.Errorf("expected the last result marked stale, got stale=%v %+v", res.Stale, res.Products)
+	}
+
+	// Without a previous result, or on other errors, the failure is returned.
+	if _, err := uc.Search(ctx, domain.SearchRequest{Query: "laptop"}); !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("expected ErrUpstreamUnavailable without a cached result, got %v", err)
+	}
+	ag.err = errors.New("invalid request")
+	if _, err := uc.Search(ctx, req); err == nil {
+		t.Error("expected a non-availability error to fail the search")
+	}
+}
+
+func TestSearch_CoalescesConcurrentSearches(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}, block: make(chan struct{})}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, newMemCacheGateway(), nil)
+	req := domain.SearchRequest{Query: "phone"}
+
+	const callers = 5
+	results := make(chan error, callers)
+	search := func(ctx context.Context) {
+		res, err := uc.Search(ctx, req)
+		if err == nil && (len(res.Products) != 1 || res.Products[0].ID != "1") {
+			err = fmt.Errorf("unexpected products %+v", res.Products)
+		}
+		results <- err
+	}
+	go search(searchCtx("en"))
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&ag.calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A caller giving up does not cancel the shared pipeline.
+	cancelled, cancel := context.WithCancel(searchCtx("en"))
+	cancelledErr := make(chan error, 1)
+	go func() {
+		_, err := uc.Search(cancelled, req)
+		cancelledErr <- err
+	}()
+	for i := 1; i < callers; i++ {
+		go search(searchCtx("en"))
+	}
+	cancel()
+	if err := <-cancelledErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled caller to get context.Canceled, got %v", err)
+	}
+
+	close(ag.block)
+	for i := 0; i < callers; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("search failed: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 1 {
+		t.Errorf("expected concurrent identical searches to share 1 upstream fetch, got %d", got)
+	}
+}
+
+func TestSearch_Pagination(t *testing.T) {
+	// 120 upstream results over three pages of MaxPageSize.
+	pages := map[int][]*domain.Product{}
+	for i := 0; i < 120; i++ {
+		pageNo := i/MaxPageSize + 1
+		pages[pageNo] = append(pages[pageNo], &domain.Product{ID: strconv.Itoa(i)})
+	}
+	ag := &fakeAlibabaGateway{pages: pages, total: 120}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.CandidateTarget = 3 * MaxPageSize
+	uc.FetchInterval = 0
+
+	shown := make(map[string]bool)
+	cursor := ""
+	for want := 1; want <= 6; want++ {
+		req := domain.SearchRequest{Query: "phone", Page: 1, PageSize: 20}
+		if cursor != "" {
+			page, size, err := DecodePageCursor(cursor)
+			if err != nil {
+				t.Fatalf("bad next cursor: %v", err)
+			}
+			req.Page, req.PageSize = page, size
+		}
+		res, err := uc.Search(searchCtx("en"), req)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if res.Page.TotalRecordCount != 120 || res.Page.CurrentPageNo != want || res.Page.PageSize != 20 {
+			t.Errorf("unexpected page info: %+v", res.Page)
+		}
+		if len(res.Products) != 20 {
+			t.Errorf("page %d: expected 20 products, got %d", want, len(res.Products))
+		}
+		for _, p := range res.Products {
+			if shown[p.ID] {
+				t.Errorf("page %d repeats product %s", want, p.ID)
+			}
+			shown[p.ID] = true
+		}
+		cursor = res.Page.NextCursor
+	}
+	if cursor != "" {
+		t.Errorf("expected no next cursor on the last page, got %q", cursor)
+	}
+	if len(shown) != 120 {
+		t.Errorf("expected every upstream result to be reachable, got %d", len(shown))
+	}
+	if ag.lastSize != MaxPageSize {
+		t.Errorf("expected upstream pages of %d, got %d", MaxPageSize, ag.lastSize)
+	}
+}
+
+func TestSearch_PagePastPoolIsEmpty(t *testing.T) {
+	ag := &concurrencyProbeGateway{}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.FetchInterval = 0
+
+	for _, req := range []domain.SearchRequest{
+		// (page-1)*pageSize used to overflow into a negative slice bound.
+		{Query: "phone", Page: 288230376151711745, PageSize: 50},
+		// The largest page a forged cursor could carry.
+		{Query: "phone", Page: math.MaxInt, PageSize: 1},
+		// The first page past the pool.
+		{Query: "phone", Page: maxPoolResults/20 + 1, PageSize: 20},
+	} {
+		res, err := uc.Search(searchCtx("en"), req)
+		if err != nil {
+			t.Fatalf("page %d: search failed: %v", req.Page, err)
+		}
+		if len(res.Products) != 0 || res.Page.NextCursor != "" {
+			t.Errorf("page %d: expected an empty last page, got %d products and cursor %q", req.Page, len(res.Products), res.Page.NextCursor)
+		}
+	}
+
+	// The last page of the largest pool is still served.
+	uc.CandidateTarget = maxPoolResults
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone", Page: maxPoolResults / 20, PageSize: 20})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(res.Products) != 20 || res.Page.NextCursor != "" {
+		t.Errorf("expected a full last page without a cursor, got %d products and cursor %q", len(res.Products), res.Page.NextCursor)
+	}
+}
+
+func TestSearch_PagesShareOneRanking(t *testing.T) {
+	// Sales vary across upstream pages, and page 3 holds the best seller, so
+	// a pool grown for deeper pages would normalize and rank differently.
+	pages := map[int][]*domain.Product{}
+	for i := 0; i < 3*MaxPageSize; i++ {
+		pageNo := i/MaxPageSize + 1
+		pages[pageNo] = append(pages[pageNo], &domain.Product{ID: strconv.Itoa(i), NumberSold: (i * 37) % 101, ProductRating: float64(i%5) + 0.5})
+	}
+	pages[3][0].NumberSold = 1000000
+	ag := &fakeAlibabaGateway{pages: pages, total: 10000}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.FetchInterval = 0
+
+	shown := make(map[string]bool)
+	req := domain.SearchRequest{Query: "phone", Page: 1, PageSize: 30}
+	for pages := 0; ; pages++ {
+		if pages > LastPage(req.PageSize) {
+			t.Fatal("cursor never ran out")
+		}
+		res, err := uc.Search(searchCtx("en"), req)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		for _, p := range res.Products {
+			if shown[p.ID] {
+				t.Errorf("page %d repeats product %s", req.Page, p.ID)
+			}
+			shown[p.ID] = true
+		}
+		if res.Page.NextCursor == "" {
+			break
+		}
+		if req.Page, req.PageSize, err = DecodePageCursor(res.Page.NextCursor); err != nil {
+			t.Fatalf("bad next cursor: %v", err)
+		}
+	}
+	// Every page is cut from the first two upstream pages, without gaps.
+	if len(shown) != DefaultCandidateTarget {
+		t.Errorf("expected the %d pool products across the pages, got %d", DefaultCandidateTarget, len(shown))
+	}
+	for i := 0; i < DefaultCandidateTarget; i++ {
+		if !shown[strconv.Itoa(i)] {
+			t.Errorf("product %d of the pool was never shown", i)
+		}
+	}
+}
+
+func TestSearch_RanksCandidatePool(t *testing.T) {
+	pages := map[int][]*domain.Product{}
+	for i := 0; i < 2*MaxPageSize; i++ {
+		pageNo := i/MaxPageSize + 1
+		pages[pageNo] = append(pages[pageNo], &domain.Product{ID: strconv.Itoa(i), Price: domain.Price{USD: float64(200 - i)}})
+	}
+	// Page 2 repeats a product of page 1.
+	pages[2][0] = &domain.Product{ID: "0", Price: domain.Price{USD: 200}}
+	ag := &fakeAlibabaGateway{pages: pages, total: 5000}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.FetchInterval = 0
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone", PageSize: 5, Filters: domain.SearchFilters{Sort: RankCheapest}})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	// The cheapest products are on the second upstream page.
+	if got := ids(res.Products); len(got) != 5 || got[0] != "99" || got[4] != "95" {
+		t.Errorf("expected the cheapest of the whole pool, got %v", got)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 2 {
+		t.Errorf("expected %d upstream pages for the default target, got %d", DefaultCandidateTarget/MaxPageSize, got)
+	}
+
+	// The pool stops at the last upstream page.
+	ag = &fakeAlibabaGateway{pages: pages, total: 30}
+	uc = NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	if _, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 1 {
+		t.Errorf("expected no fetches past total_record_count, got %d", got)
+	}
+}
+
+func TestSearch_FetchesPagesConcurrentlyWithinLimits(t *testing.T) {
+	ag := &concurrencyProbeGateway{delay: 20 * time.Millisecond}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.CandidateTarget = 6 * MaxPageSize
+	uc.FetchConcurrency = 2
+	uc.FetchInterval = 0
+
+	if _, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&ag.calls); got != 6 {
+		t.Errorf("expected 6 page fetches, got %d", got)
+	}
+	if got := atomic.LoadInt32(&ag.maxObs); got != 2 {
+		t.Errorf("expected the extra pages to be fetched 2 at a time, saw %d", got)
+	}
+
+	// The interval spaces the extra page fetches.
+	ag = &concurrencyProbeGateway{}
+	uc = NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, nil)
+	uc.CandidateTarget = 4 * MaxPageSize
+	uc.FetchInterval = 30 * time.Millisecond
+	start := time.Now()
+	if _, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("expected 3 extra pages to take at least 2 intervals, took %s", elapsed)
+	}
+}
+
+// concurrencyProbeGateway serves full pages of distinct products from a
+// large result set and records how many fetches overlap.
+type concurrencyProbeGateway struct {
+	fakeAlibabaGateway
+	delay            time.Duration
+	inFlight, maxObs int32
+}
+
+func (g *concurrencyProbeGateway) FetchProducts(ctx context.Context, intent domain.SearchIntent, pageNo, pageSize int) (*domain.ProductPage, error) {
+	atomic.AddInt32(&g.calls, 1)
+	n := atomic.AddInt32(&g.inFlight, 1)
+	defer atomic.AddInt32(&g.inFlight, -1)
+	for {
+		seen := atomic.LoadInt32(&g.maxObs)
+		if n <= seen || atomic.CompareAndSwapInt32(&g.maxObs, seen, n) {
+			break
+		}
+	}
+	time.Sleep(g.delay)
+	products := make([]*domain.Product, pageSize)
+	for i := range products {
+		products[i] = &domain.Product{ID: strconv.Itoa((pageNo-1)*pageSize + i)}
+	}
+	return &domain.ProductPage{Products: products, TotalRecordCount: 10000, CurrentPageNo: pageNo}, nil
+}
+
+func TestDecodePageCursor_Invalid(t *testing.T) {
+	for _, c := range []string{"garbage!", EncodePageCursor(0, 10), EncodePageCursor(1, MaxPageSize+1), EncodePageCursor(LastPage(20)+1, 20)} {
+		if _, _, err := DecodePageCursor(c); err == nil {
+			t.Errorf("expected error for cursor %q", c)
+		}
+	}
+}
+
+func TestSearch_ExplicitFiltersOverrideIntent(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{
+		Keywords: "phone",
+		MaxPrice: floatPtr(100),
+		MinPrice: floatPtr(10),
+		Currency: "ETB",
+	}}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+
+	maxPrice := 50.0
+	days := 7
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{
+		Query: "phone under 2000 birr",
+		Filters: domain.SearchFilters{
+			MaxPrice:        &maxPrice,
+			Currency:        "USD",
+			MaxDeliveryDays: &days,
+			Sort:            "price_asc",
+		},
+	})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	got := ag.lastIntent
+	if got.MaxPrice == nil || *got.MaxPrice != 50 {
+		t.Errorf("explicit maxPrice should win, got %v", got.MaxPrice)
+	}
+	// The parsed 10 ETB minimum is not reread as 10 USD.
+	if got.MinPrice != nil {
+		t.Errorf("LLM minPrice in another currency should be dropped, got %v", *got.MinPrice)
+	}
+	if got.Sort != "price_asc" || got.MaxDeliveryDays == nil || *got.MaxDeliveryDays != 7 || got.Currency != "USD" {
+		t.Errorf("sort/delivery/currency not forwarded: %+v", got)
+	}
+
+	f := res.Filters
+	if f.MaxPrice == nil || *f.MaxPrice != 50 || f.MinPrice != nil {
+		t.Errorf("unexpected effective price range: %+v", f)
+	}
+	if len(res.IntentWarnings) != 1 || res.IntentWarnings[0].Field != "minPrice" {
+		t.Errorf("expected the dropped minPrice to be reported, got %+v", res.IntentWarnings)
+	}
+	if f.Currency != "USD" || f.Sort != "price_asc" || f.MaxDeliveryDays == nil || *f.MaxDeliveryDays != 7 {
+		t.Errorf("unexpected effective filters: %+v", f)
+	}
+}
+
+func TestSearch_ExplicitPriceCurrency(t *testing.T) {
+	maxPrice := 50.0
+	search := func(parsed domain.SearchIntent, filters domain.SearchFilters, fx domain.IFXClient) domain.SearchIntent {
+		t.Helper()
+		ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}
+		uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{intent: parsed}, nil, fx)
+		if _, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone", Filters: filters}); err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		return ag.lastIntent
+	}
+
+	// Without a currency the bound is USD, whichever currency the query named.
+	for _, parsed := range []domain.SearchIntent{
+		{Keywords: "phone", MinPrice: floatPtr(1000), Currency: "ETB"},
+		{Keywords: "phone", MinPrice: floatPtr(5), Currency: "USD"},
+		{Keywords: "phone"},
+	} {
+		got := search(parsed, domain.SearchFilters{MaxPrice: &maxPrice}, nil)
+		if got.MaxPrice == nil || *got.MaxPrice != 50 || got.Currency != "USD" {
+			t.Errorf("parsed %+v: expected a 50 USD bound, got %+v", parsed, got)
+		}
+	}
+
+	// With a currency the bound is converted from it.
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(125.0, nil).Once()
+	got := search(domain.SearchIntent{Keywords: "phone", Currency: "USD"}, domain.SearchFilters{MaxPrice: &maxPrice, Currency: "ETB"}, fx)
+	if got.MaxPrice == nil || *got.MaxPrice != 0.4 || got.Currency != "USD" {
+		t.Errorf("expected 50 ETB converted to 0.40 USD, got %+v", got)
+	}
+}
+
+func TestSearch_FillsETBPrices(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1", Price: domain.Price{USD: 10}}}}
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(56.5, nil).Once()
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, fx)
+
+	res, err := uc.Search(searchCtx("am"), domain.SearchRequest{Query: "phone"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if res.FXDegraded {
+		t.Error("expected fxDegraded=false when a rate is available")
+	}
+	p := res.Products[0]
+	if p.Price.ETB != 565 || p.Price.FXTimestamp.IsZero() {
+		t.Errorf("unexpected ETB price: %+v", p.Price)
+	}
+}
+
+func TestSearch_FXUnavailableIsDegraded(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1", Price: domain.Price{USD: 10}}}}
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(0.0, errors.New("provider down")).Once()
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, nil, fx)
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"})
+	if err != nil {
+		t.Fatalf("search should not fail when FX is down: %v", err)
+	}
+	if !res.FXDegraded {
+		t.Error("expected fxDegraded=true when no rate is available")
+	}
+	if res.Products[0].Price.ETB != 0 {
+		t.Errorf("expected ETB to stay unset, got %v", res.Products[0].Price.ETB)
+	}
+}
+
+func TestSearch_ConvertsETBBudgetToUSD(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1", Price: domain.Price{USD: 10}}}}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{Keywords: "phone", MaxPrice: floatPtr(20000), Currency: "ETB"}}
+	fx := mocks.NewIFXClient(t)
+	fx.On("GetRate", mock.Anything, "USD", "ETB").Return(125.0, nil).Once()
+	uc := NewSearchProductsUseCase(ag, lg, nil, fx)
+
+	res, err := uc.Search(searchCtx("am"), domain.SearchRequest{Query: "phone under 20000 birr"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := ag.lastIntent; got.MaxPrice == nil || *got.MaxPrice != 160 || got.Currency != "USD" {
+		t.Errorf("expected ETB budget converted to 160 USD, got %+v", got)
+	}
+	if res.BudgetFXRate != 125 {
+		t.Errorf("expected budget rate to be recorded, got %v", res.BudgetFXRate)
+	}
+	if res.Filters.Currency != "ETB" || *res.Filters.MaxPrice != 20000 {
+		t.Errorf("effective filters should echo the ETB budget, got %+v", res.Filters)
+	}
+}
+
+func TestSearch_DropsETBBudgetWithoutRate(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{MaxPrice: floatPtr(20000), Currency: "ETB"}}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+
+	if _, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if ag.lastIntent.MaxPrice != nil {
+		t.Errorf("unconvertible ETB budget must not be sent as USD: %+v", ag.lastIntent)
+	}
+}
+
+func TestSearchStream_EmitsEventsInOrder(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}, {ID: "2"}}}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{Keywords: "mobile phone"}}
+	uc := NewSearchProductsUseCase(ag, lg, newMemCacheGateway(), nil)
+
+	var events []string
+	var intents []IntentEvent
+	record := func(event string, data interface{}) {
+		events = append(events, event)
+		if ie, ok := data.(IntentEvent); ok {
+			intents = append(intents, ie)
+		}
+	}
+	res, err := uc.SearchStream(searchCtx("en"), domain.SearchRequest{Query: "phone pls"}, record)
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	want := []string{SearchEventIntent, SearchEventProducts, SearchEventProduct, SearchEventProduct, SearchEventDone}
+	if len(events) != len(want) {
+		t.Fatalf("unexpected events: %v", events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("unexpected events: %v", events)
+		}
+	}
+	if len(res.Products) != 2 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	// A second stream is served from the cache without another fetch.
+	events = nil
+	if _, err := uc.SearchStream(searchCtx("en"), domain.SearchRequest{Query: "phone pls"}, record); err != nil {
+		t.Fatalf("cached stream failed: %v", err)
+	}
+	if atomic.LoadInt32(&ag.calls) != 1 || len(events) != 3 || events[2] != SearchEventDone {
+		t.Errorf("expected cached replay, got %d fetches and events %v", ag.calls, events)
+	}
+	// The replay reports the parsed keywords, like the live stream.
+	if len(intents) != 2 || intents[0].Keywords != "mobile phone" || intents[1].Keywords != "mobile phone" {
+		t.Errorf("expected the parsed keywords on both streams, got %+v", intents)
+	}
+}
+
+func TestSearch_SummarizationIsBounded(t *testing.T) {
+	products := make([]*domain.Product, 8)
+	for i := range products {
+		products[i] = &domain.Product{ID: string(rune('a' + i))}
+	}
+	ag := &fakeAlibabaGateway{products: products}
+	lg := &fakeLLMGateway{delay: 20 * time.Millisecond}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+	uc.SummaryConcurrency = 2
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&lg.maxObs); got > 2 {
+		t.Errorf("expected at most 2 concurrent summaries, observed %d", got)
+	}
+	for _, p := range res.Products {
+		if !p.AIEnriched {
+			t.Errorf("product %s should be AI enriched", p.ID)
+		}
+	}
+}
+
+func TestSearch_SummarizationBudgetFallsBack(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+	lg := &fakeLLMGateway{delay: time.Second}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+	uc.SummaryBudget = 50 * time.Millisecond
+
+	start := time.Now()
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("search did not respect the summarization budget: %v", elapsed)
+	}
+	if len(res.Products) != 3 {
+		t.Fatalf("expected all products to be returned, got %d", len(res.Products))
+	}
+	for _, p := range res.Products {
+		if p.AIEnriched {
+			t.Errorf("product %s should carry the fallback summary", p.ID)
+		}
+	}
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func intPtr(v int) *int { return &v }
+
+func TestSearch_ReportsIntentWarnings(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}
+	lg := &fakeLLMGateway{intent: domain.SearchIntent{MinPrice: floatPtr(300), Currency: "USD"}}
+	uc := NewSearchProductsUseCase(ag, lg, nil, nil)
+
+	maxPrice := 100.0
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone", Filters: domain.SearchFilters{MaxPrice: &maxPrice}})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if ag.lastIntent.MinPrice != nil || ag.lastIntent.Keywords != "phone" {
+		t.Errorf("expected clamped intent with query keywords, got %+v", ag.lastIntent)
+	}
+	if len(res.IntentWarnings) != 1 || res.IntentWarnings[0].Field != "minPrice" {
+		t.Errorf("expected a minPrice warning, got %+v", res.IntentWarnings)
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+const (
+	// DefaultSessionTTL is how long a search session survives without a follow-up.
+	DefaultSessionTTL = 30 * time.Minute
+	// maxSessionSeenIDs bounds the seen-product list kept per session.
+	maxSessionSeenIDs = 500
+)
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+// loadSession returns the session named by the request, or nil when the
+// request starts a new search or the session has expired. A cached result's
+// session ID is its cache key, which starts a new session holding that
+// result's intent and products; so only follow-ups write sessions.
+func (uc *SearchProductsUseCase) loadSession(ctx context.Context, id string) *domain.SearchSession {
+	if id == "" || uc.cacheGateway == nil {
+		return nil
+	}
+	if strings.HasPrefix(id, searchCacheKeyPrefix) {
+		entry, ok := uc.readCache(ctx, id)
+		if !ok {
+			log.Println("SearchProductsUseCase: unknown or expired search for session:", id)
+			return nil
+		}
+		sess := &domain.SearchSession{ID: uuid.NewString()}
+		if entry.Result.Intent != nil {
+			sess.Intent = *entry.Result.Intent
+		}
+		for _, p := range entry.Result.Products {
+			sess.SeenIDs = append(sess.SeenIDs, p.ID)
+		}
+		return sess
+	}
+	raw, err := uc.cacheGateway.Get(ctx, sessionKey(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrCacheMiss) {
+			log.Println("SearchProductsUseCase: unknown or expired session:", id)
+		} else {
+			log.Println("SearchProductsUseCase: session read failed for:", id, "error:", err)
+		}
+		return nil
+	}
+	var sess domain.SearchSession
+	if err := json.Unmarshal([]byte(raw), &sess); err != nil || sess.ID != id {
+		log.Println("SearchProductsUseCase: discarding unreadable session:", id)
+		return nil
+	}
+	return &sess
+}
+
+// saveSession records the result's intent and products on sess and returns a
+// copy of result carrying the session ID.
+func (uc *SearchProductsUseCase) saveSession(ctx context.Context, sess *domain.SearchSession, result *domain.SearchResult) *domain.SearchResult {
+	if result.Intent != nil {
+		sess.Intent = *result.Intent
+	}
+	for _, p := range result.Products {
+		sess.SeenIDs = append(sess.SeenIDs, p.ID)
+	}
+	if n := len(sess.SeenIDs); n > maxSessionSeenIDs {
+		sess.SeenIDs = sess.SeenIDs[n-maxSessionSeenIDs:]
+	}
+	sess.UpdatedAt = time.Now().UTC()
+
+	if err := uc.cacheGateway.Set(ctx, sessionKey(sess.ID), sess, uc.SessionTTL); err != nil {
+		log.Println("SearchProductsUseCase: session write failed for:", sess.ID, "error:", err)
+		return result
+	}
+	out := *result
+	out.SessionID = sess.ID
+	return &out
+}
+
+// resolveIntent parses the query into an intent, or for a follow-up in a
+// session asks the LLM for a delta and applies it to the previous intent.
+func (uc *SearchProductsUseCase) resolveIntent(ctx context.Context, query string, sess *domain.SearchSession) domain.SearchIntent {
+	if sess != nil {
+		delta, err := uc.llmGateway.RefineIntent(ctx, sess.Intent, sess.SeenIDs, query)
+		if err == nil && delta != nil {
+			log.Printf("SearchProductsUseCase: refined session %s intent with %+v", sess.ID, *delta)
+			return sess.Intent.Apply(*delta)
+		}
+		// Fail soft by repeating the previous search; seen results are still excluded
+		log.Println("SearchProductsUseCase: LLM intent refinement failed for query:", query, "error:", err)
+		return sess.Intent
+	}
+
+	parsed, err := uc.llmGateway.ParseIntent(ctx, query)
+	if err != nil || parsed == nil {
+		// Fail soft by searching for the raw query without constraints
+		log.Println("SearchProductsUseCase: LLM intent parsing failed for query:", query, "error:", err)
+		return domain.SearchIntent{}
+	}
+	return *parsed
+}
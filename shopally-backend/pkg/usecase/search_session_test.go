@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+func TestSearch_FollowUpRefinesSessionAndExcludesSeen(t *testing.T) {
+	ag := &fakeAlibabaGateway{total: 2 * MaxPageSize, pages: map[int][]*domain.Product{
+		1: {{ID: "1", Price: domain.Price{USD: 30}}, {ID: "2", Price: domain.Price{USD: 20}}},
+		2: {{ID: "3", Price: domain.Price{USD: 15}}},
+	}}
+	lg := &fakeLLMGateway{
+		intent: domain.SearchIntent{Keywords: "phone", MaxPrice: floatPtr(40), Currency: "USD"},
+		delta:  domain.IntentDelta{Set: domain.SearchIntent{Keywords: "red phone", Sort: RankCheapest}},
+	}
+	cache := newMemCacheGateway()
+	uc := NewSearchProductsUseCase(ag, lg, cache, nil)
+	// A one-page pool, so the follow-up backfills past the seen results.
+	uc.CandidateTarget = MaxPageSize
+	uc.FetchInterval = 0
+
+	first, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone under $40", PageSize: 2})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if first.SessionID == "" {
+		t.Fatal("expected a session ID on the first search")
+	}
+
+	second, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "only red, cheaper", PageSize: 2, SessionID: first.SessionID})
+	if err != nil {
+		t.Fatalf("follow-up failed: %v", err)
+	}
+
+	// The first follow-up starts a session of its own from the cached result.
+	if second.SessionID == "" || second.SessionID == first.SessionID {
+		t.Errorf("follow-up should start its own session, got %q", second.SessionID)
+	}
+	if len(lg.lastSeen) != 2 {
+		t.Errorf("LLM should see the previously shown IDs, got %v", lg.lastSeen)
+	}
+	got := ag.lastIntent
+	if got.Keywords != "red phone" || got.Sort != RankCheapest || got.MaxPrice == nil || *got.MaxPrice != 40 {
+		t.Errorf("expected merged intent, got %+v", got)
+	}
+	if shown := ids(second.Products); len(shown) != 1 || shown[0] != "3" {
+		t.Errorf("already seen results should be excluded, got %v", shown)
+	}
+
+	sess := uc.loadSession(searchCtx("en"), second.SessionID)
+	if sess == nil || len(sess.SeenIDs) != 3 || sess.Intent.Keywords != "red phone" {
+		t.Errorf("session not updated: %+v", sess)
+	}
+
+	third, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "any others", PageSize: 2, SessionID: second.SessionID})
+	if err != nil {
+		t.Fatalf("second follow-up failed: %v", err)
+	}
+	if third.SessionID != second.SessionID {
+		t.Errorf("later follow-ups should keep the session, got %q", third.SessionID)
+	}
+}
+
+func TestSearch_OnlyFollowUpsWriteSessions(t *testing.T) {
+	cache := newMemCacheGateway()
+	uc := NewSearchProductsUseCase(&fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}, &fakeLLMGateway{}, cache, nil)
+
+	var sessionID string
+	for i := 0; i < 3; i++ {
+		res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if sessionID = res.SessionID; sessionID == "" {
+			t.Fatal("expected a session ID on every result")
+		}
+	}
+	for key := range cache.data {
+		if strings.HasPrefix(key, "session:") {
+			t.Errorf("new searches should not write sessions, found %s", key)
+		}
+	}
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "cheaper", SessionID: sessionID})
+	if err != nil {
+		t.Fatalf("follow-up failed: %v", err)
+	}
+	if _, ok := cache.data[sessionKey(res.SessionID)]; !ok {
+		t.Errorf("expected the follow-up to write session %q", res.SessionID)
+	}
+}
+
+func TestSearch_UnknownSessionStartsNewOne(t *testing.T) {
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}
+	uc := NewSearchProductsUseCase(ag, &fakeLLMGateway{}, newMemCacheGateway(), nil)
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone", SessionID: "expired"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if res.SessionID == "" || res.SessionID == "expired" {
+		t.Errorf("expected a fresh session ID, got %q", res.SessionID)
+	}
+}
+
+func TestSearch_NoSessionsWithoutCache(t *testing.T) {
+	uc := NewSearchProductsUseCase(&fakeAlibabaGateway{products: []*domain.Product{{ID: "1"}}}, &fakeLLMGateway{}, nil, nil)
+
+	res, err := uc.Search(searchCtx("en"), domain.SearchRequest{Query: "phone"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if res.SessionID != "" {
+		t.Errorf("sessions need a cache, got %q", res.SessionID)
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// Events emitted by SearchStream, in order: one intent, one products, one
+// product per enhanced item, then done.
+const (
+	SearchEventIntent   = "intent"
+	SearchEventProducts = "products"
+	SearchEventProduct  = "product"
+	SearchEventDone     = "done"
+)
+
+// SearchObserver receives search pipeline progress events.
+type SearchObserver func(event string, data interface{})
+
+// IntentEvent is the payload of SearchEventIntent.
+type IntentEvent struct {
+	Keywords string               `json:"keywords"`
+	Filters  domain.SearchFilters `json:"filters"`
+}
+
+// ProductsEvent is the payload of SearchEventProducts: ranked, priced products
+// before LLM enhancement.
+type ProductsEvent struct {
+	Products   []*domain.Product `json:"products"`
+	Page       domain.PageInfo   `json:"page"`
+	FXDegraded bool              `json:"fxDegraded"`
+}
+
+// ProductEvent is the payload of SearchEventProduct.
+type ProductEvent struct {
+	Index   int             `json:"index"`
+	Product *domain.Product `json:"product"`
+}
+
+// SearchStream runs the same pipeline as Search but reports progress through
+// emit as it goes. Calls to emit are serialized. Cached results are handled as
+// in Search: a fresh or stale one is replayed as intent, products and done
+// events without re-running the pipeline, a stale one also triggering a
+// background refresh, and an older one is replayed, marked Stale, when the
+// product source is unavailable.
+func (uc *SearchProductsUseCase) SearchStream(ctx context.Context, req domain.SearchRequest, emit SearchObserver) (*domain.SearchResult, error) {
+	req = normalizeSearchRequest(req)
+
+	var mu sync.Mutex
+	serialized := func(event string, data interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		emit(event, data)
+	}
+
+	if sess := uc.loadSession(ctx, req.SessionID); sess != nil {
+		result, err := uc.runPipeline(ctx, req, sess, serialized)
+		if err != nil {
+			return nil, err
+		}
+		result = uc.saveSession(ctx, sess, result)
+		serialized(SearchEventDone, result)
+		return result, nil
+	}
+
+	var key string
+	var entry *cachedSearch
+	if uc.cacheGateway != nil {
+		key = searchCacheKey(ctx, req)
+		entry, _ = uc.readCache(ctx, key)
+		if entry != nil {
+			now := time.Now()
+			if now.Before(entry.FreshUntil) {
+				log.Println("SearchProductsUseCase: streaming cached result for query:", req.Query)
+				return replayResult(req, entry.Result, serialized), nil
+			}
+			if now.Before(entry.FreshUntil.Add(uc.StaleTTL)) {
+				log.Println("SearchProductsUseCase: streaming stale result and refreshing for query:", req.Query)
+				uc.refreshInBackground(ctx, key, req)
+				return replayResult(req, entry.Result, serialized), nil
+			}
+		}
+	}
+
+	result, err := uc.runPipeline(ctx, req, nil, serialized)
+	if err != nil {
+		if entry != nil && errors.Is(err, domain.ErrUpstreamUnavailable) {
+			log.Println("SearchProductsUseCase: product source unavailable, streaming last cached result for query:", req.Query, "error:", err)
+			stale := *entry.Result
+			stale.Stale = true
+			return replayResult(req, &stale, serialized), nil
+		}
+		return nil, err
+	}
+	if key != "" {
+		uc.writeCache(ctx, key, result)
+	}
+	serialized(SearchEventDone, result)
+	return result, nil
+}
+
+// replayResult emits a cached result as intent, products and done events.
+// The intent event carries the keywords the result was parsed into, as the
+// live stream does.
+func replayResult(req domain.SearchRequest, res *domain.SearchResult, emit SearchObserver) *domain.SearchResult {
+	keywords := req.Query
+	if res.Intent != nil && res.Intent.Keywords != "" {
+		keywords = res.Intent.Keywords
+	}
+	emit(SearchEventIntent, IntentEvent{Keywords: keywords, Filters: res.Filters})
+	emit(SearchEventProducts, ProductsEvent{Products: res.Products, Page: res.Page, FXDegraded: res.FXDegraded})
+	emit(SearchEventDone, res)
+	return res
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+type productSummary struct {
+	index   int
+	product *domain.Product
+}
+
+// summarizeProducts enhances products with the LLM using at most
+// SummaryConcurrency calls at a time, each bounded by SummaryTimeout. Once
+// SummaryBudget has elapsed, products still waiting are given the gateway's
+// heuristic FallbackSummary so the search returns on time. Each finished
+// product is reported through emit.
+func (uc *SearchProductsUseCase) summarizeProducts(ctx context.Context, products []*domain.Product, userPrompt string, emit SearchObserver) {
+	budgetCtx, cancel := context.WithTimeout(ctx, uc.SummaryBudget)
+	defer cancel()
+
+	limit := uc.SummaryConcurrency
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	// Buffered so late workers never block after the budget has expired.
+	results := make(chan productSummary, len(products))
+
+	pending := 0
+	for i, p := range products {
+		if p == nil {
+			continue
+		}
+		pending++
+		go func(index int, p *domain.Product) {
+			select {
+			case sem <- struct{}{}:
+			case <-budgetCtx.Done():
+				results <- productSummary{index: index}
+				return
+			}
+			defer func() { <-sem }()
+
+			pctx, pcancel := context.WithTimeout(budgetCtx, uc.SummaryTimeout)
+			defer pcancel()
+			enhanced, err := uc.llmGateway.SummarizeProduct(pctx, p, userPrompt)
+			if err != nil {
+				log.Println("SearchProductsUseCase: summarization failed for product:", p.ID, "error:", err)
+				enhanced = nil
+			}
+			results <- productSummary{index: index, product: enhanced}
+		}(i, p)
+	}
+
+	finished := make([]bool, len(products))
+	finish := func(r productSummary) {
+		finished[r.index] = true
+		if r.product != nil {
+			products[r.index] = r.product
+		} else {
+			products[r.index] = uc.llmGateway.FallbackSummary(ctx, products[r.index], userPrompt)
+		}
+		emit(SearchEventProduct, ProductEvent{Index: r.index, Product: products[r.index]})
+	}
+
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			finish(r)
+		case <-budgetCtx.Done():
+			log.Println("SearchProductsUseCase: summarization budget exhausted,", pending, "products use the fallback summary")
+			for i, p := range products {
+				if p != nil && !finished[i] {
+					finish(productSummary{index: i})
+				}
+			}
+			pending = 0
+		}
+	}
+}
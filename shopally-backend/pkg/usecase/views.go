@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+const (
+	// DefaultRecentlyViewedLimit is the number of recently viewed products returned by default.
+	DefaultRecentlyViewedLimit = 20
+	// MaxRecentlyViewedLimit caps the recently viewed products returned.
+	MaxRecentlyViewedLimit = 50
+	// DefaultPopularWindow is the period whose views count towards the popularity signal.
+	DefaultPopularWindow = 6 * time.Hour
+
+	// recentLookupConcurrency caps simultaneous product loads for the recently viewed list.
+	recentLookupConcurrency = 5
+)
+
+// ProductViewsUseCase records product views per device and serves the
+// recently viewed list and the "popular now" view counts.
+type ProductViewsUseCase struct {
+	repo domain.ViewRepository
+
+	// Products, if set, loads each recently viewed product; otherwise only
+	// IDs and view times are returned.
+	Products *GetProductDetailUseCase
+	// PopularWindow is the period whose views make up a product's RecentViews.
+	PopularWindow time.Duration
+}
+
+// NewProductViewsUseCase creates a new ProductViewsUseCase.
+func NewProductViewsUseCase(repo domain.ViewRepository) *ProductViewsUseCase {
+	return &ProductViewsUseCase{repo: repo, PopularWindow: DefaultPopularWindow}
+}
+
+// RecordView records that deviceID viewed productID now.
+func (uc *ProductViewsUseCase) RecordView(ctx context.Context, deviceID, productID string) error {
+	return uc.repo.RecordView(ctx, deviceID, productID, time.Now().UTC())
+}
+
+// RecentlyViewed returns the device's most recently viewed products, newest
+// first. limit <= 0 uses the default. Products that no longer exist are
+// dropped; products that fail to load are returned without details.
+func (uc *ProductViewsUseCase) RecentlyViewed(ctx context.Context, deviceID string, limit int) ([]domain.RecentView, error) {
+	if limit <= 0 {
+		limit = DefaultRecentlyViewedLimit
+	}
+	limit = min(limit, MaxRecentlyViewedLimit)
+	views, err := uc.repo.RecentViews(ctx, deviceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if uc.Products == nil || len(views) == 0 {
+		return views, nil
+	}
+
+	gone := make([]bool, len(views))
+	sem := make(chan struct{}, recentLookupConcurrency)
+	var wg sync.WaitGroup
+	for i := range views {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			detail, err := uc.Products.Execute(ctx, views[i].ProductID, false)
+			switch {
+			case errors.Is(err, domain.ErrProductNotFound):
+				gone[i] = true
+			case err != nil:
+				log.Println("ProductViewsUseCase: failed to load viewed product:", views[i].ProductID, "error:", err)
+			default:
+				views[i].Product = detail.Product
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	out := views[:0]
+	for i, v := range views {
+		if !gone[i] {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// AnnotatePopularity sets RecentViews on each product from the views within
+// PopularWindow. Failures are logged and leave the counts at zero.
+func (uc *ProductViewsUseCase) AnnotatePopularity(ctx context.Context, products []*domain.Product) {
+	ids := make([]string, 0, len(products))
+	for _, p := range products {
+		if p != nil {
+			ids = append(ids, p.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	counts, err := uc.repo.ViewCounts(ctx, ids, time.Now().Add(-uc.PopularWindow))
+	if err != nil {
+		log.Println("ProductViewsUseCase: failed to load view counts, error:", err)
+		return
+	}
+	for _, p := range products {
+		if p != nil {
+			p.RecentViews = counts[p.ID]
+		}
+	}
+}
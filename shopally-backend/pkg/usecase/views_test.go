@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductViews_RecentlyViewedLoadsProducts(t *testing.T) {
+	repo := mocks.NewViewRepository(t)
+	now := time.Now().UTC()
+	repo.On("RecentViews", mock.Anything, "dev-1", MaxRecentlyViewedLimit).Return([]domain.RecentView{
+		{ProductID: "2", ViewedAt: now},
+		{ProductID: "gone", ViewedAt: now.Add(-time.Minute)},
+		{ProductID: "1", ViewedAt: now.Add(-2 * time.Minute)},
+	}, nil).Once()
+
+	ag := &fakeAlibabaGateway{products: []*domain.Product{{ID: "1", Title: "one"}, {ID: "2", Title: "two"}}}
+	uc := NewProductViewsUseCase(repo)
+	uc.Products = NewGetProductDetailUseCase(ag, nil, nil, nil)
+
+	views, err := uc.RecentlyViewed(context.Background(), "dev-1", 500)
+	if err != nil {
+		t.Fatalf("recently viewed failed: %v", err)
+	}
+	if len(views) != 2 || views[0].ProductID != "2" || views[1].ProductID != "1" {
+		t.Fatalf("expected missing products dropped in view order, got %+v", views)
+	}
+	if views[0].Product == nil || views[0].Product.Title != "two" {
+		t.Errorf("expected product details loaded, got %+v", views[0].Product)
+	}
+}
+
+func TestProductViews_PopularProfileRanksByRecentViews(t *testing.T) {
+	repo := mocks.NewViewRepository(t)
+	repo.On("ViewCounts", mock.Anything, []string{"a", "b", "c"}, mock.AnythingOfType("time.Time")).
+		Return(map[string]int{"b": 40, "c": 3}, nil).Once()
+	uc := NewProductViewsUseCase(repo)
+
+	products := []*domain.Product{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	uc.AnnotatePopularity(context.Background(), products)
+	NewRankers(nil)[RankPopular].Rank(products)
+	if got := ids(products); got[0] != "b" || got[1] != "c" {
+		t.Errorf("expected most viewed first, got %v", got)
+	}
+	if products[0].RecentViews != 40 || products[0].Score.Components[signalPopularity] != 100 {
+		t.Errorf("unexpected popularity annotation: %+v", products[0].Score)
+	}
+
+	repo.On("ViewCounts", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("redis down")).Once()
+	uc.AnnotatePopularity(context.Background(), products)
+	if products[0].RecentViews != 40 {
+		t.Errorf("expected counts untouched on failure, got %d", products[0].RecentViews)
+	}
+}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrCallPanicked wraps the value of a panic raised by a CallGroup call.
+var ErrCallPanicked = errors.New("call panicked")
+
+// CallGroup coalesces concurrent calls with the same key into one execution
+// whose result every caller receives, like golang.org/x/sync/singleflight.
+//
+// The shared call runs on a context that keeps the first caller's values but
+// not its cancellation. A caller whose context ends returns ctx.Err() without
+// affecting the others; the call itself is cancelled only once every caller
+// has given up. A panic in the call is returned to every caller as an error
+// instead of crashing the process. The zero value is ready to use.
+type CallGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*groupCall[T]
+}
+
+type groupCall[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// Do runs fn for key, or waits for the run already in flight for key.
+// shared reports whether the result was produced for another caller too.
+func (g *CallGroup[T]) Do(ctx context.Context, key string, fn func(context.Context) (T, error)) (v T, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*groupCall[T])
+	}
+	c, joined := g.calls[key]
+	if joined {
+		c.waiters++
+	} else {
+		cctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &groupCall[T]{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = c
+		go g.run(cctx, key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared = joined || c.waiters > 1
+		g.mu.Unlock()
+		return c.val, shared, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			// Nobody wants the result any more; later callers start afresh.
+			c.cancel()
+			g.forget(key, c)
+		}
+		g.mu.Unlock()
+		var zero T
+		return zero, joined, ctx.Err()
+	}
+}
+
+func (g *CallGroup[T]) run(ctx context.Context, key string, c *groupCall[T], fn func(context.Context) (T, error)) {
+	defer c.cancel()
+	defer func() {
+		// fn runs on its own goroutine, out of reach of any recovery
+		// middleware, so a panic is handed to the callers instead.
+		if r := recover(); r != nil {
+			var zero T
+			c.val, c.err = zero, fmt.Errorf("%w: %v\n%s", ErrCallPanicked, r, debug.Stack())
+		}
+		g.mu.Lock()
+		g.forget(key, c)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn(ctx)
+}
+
+// forget removes c from the group unless a newer call replaced it. g.mu must be held.
+func (g *CallGroup[T]) forget(key string, c *groupCall[T]) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
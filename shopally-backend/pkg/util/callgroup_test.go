@@ -0,0 +1,170 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallGroup_CoalescesConcurrentCalls(t *testing.T) {
+	var g CallGroup[int]
+	var runs int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&runs, 1)
+		<-release
+		return 42, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	var sharedCount int32
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, shared, err := g.Do(context.Background(), "k", fn)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, v)
+			if shared {
+				atomic.AddInt32(&sharedCount, 1)
+			}
+		}()
+	}
+	waitForWaiters(t, &g, "k", callers)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, runs)
+	assert.EqualValues(t, callers, sharedCount)
+
+	// A finished call is not reused.
+	_, shared, err := g.Do(context.Background(), "k", func(context.Context) (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.False(t, shared)
+}
+
+func TestCallGroup_CancelledCallerDoesNotCancelOthers(t *testing.T) {
+	var g CallGroup[string]
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		select {
+		case <-release:
+			return "ok", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(first, "k", fn)
+		firstErr <- err
+	}()
+	waitForWaiters(t, &g, "k", 1)
+
+	second := make(chan string, 1)
+	go func() {
+		v, _, err := g.Do(context.Background(), "k", fn)
+		assert.NoError(t, err)
+		second <- v
+	}()
+	waitForWaiters(t, &g, "k", 2)
+
+	cancelFirst()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "ok", <-second)
+}
+
+func TestCallGroup_CancelledWhenEveryCallerLeaves(t *testing.T) {
+	var g CallGroup[int]
+	stopped := make(chan error, 1)
+	fn := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return 0, ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err := g.Do(ctx, "k", fn)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	select {
+	case err := <-stopped:
+		assert.True(t, errors.Is(err, context.Canceled))
+	case <-time.After(time.Second):
+		t.Fatal("abandoned call was not cancelled")
+	}
+
+	// The abandoned call is not joined by later callers.
+	v, _, err := g.Do(context.Background(), "k", func(context.Context) (int, error) { return 7, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 7, v)
+}
+
+func TestCallGroup_KeepsContextValues(t *testing.T) {
+	type key struct{}
+	var g CallGroup[string]
+	ctx := context.WithValue(context.Background(), key{}, "am")
+	v, _, err := g.Do(ctx, "k", func(ctx context.Context) (string, error) {
+		return ctx.Value(key{}).(string), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "am", v)
+}
+
+func TestCallGroup_PanicIsReturnedToEveryCaller(t *testing.T) {
+	var g CallGroup[int]
+	release := make(chan struct{})
+	fn := func(context.Context) (int, error) {
+		<-release
+		panic("boom")
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, _, err := g.Do(context.Background(), "k", fn)
+			errs <- err
+		}()
+	}
+	waitForWaiters(t, &g, "k", 2)
+	close(release)
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		assert.ErrorIs(t, err, ErrCallPanicked)
+		assert.Contains(t, err.Error(), "boom")
+	}
+
+	// The failed call is forgotten.
+	v, _, err := g.Do(context.Background(), "k", func(context.Context) (int, error) { return 3, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+}
+
+// waitForWaiters blocks until n callers wait on the call for key.
+func waitForWaiters[T any](t *testing.T, g *CallGroup[T], key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		c := g.calls[key]
+		ok := c != nil && c.waiters == n
+		g.mu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d callers on %q", n, key)
+}